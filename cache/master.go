@@ -10,7 +10,7 @@ import (
 
 // cacheType, representing the abstract value of a CacheEntity
 type cacheType interface {
-	types.Weather | types.Metrics | types.Wind | types.DailyForecast | types.HourlyForecast | types.Moon
+	types.Weather | types.Metrics | types.Wind | types.DailyForecast | types.HourlyForecast | types.Moon | types.Locations
 }
 
 // CacheEntity, representing the value of the cache
@@ -33,6 +33,9 @@ type MasterCaches struct {
 	DailyForecastCache  MasterCache[types.DailyForecast]
 	HourlyForecastCache MasterCache[types.HourlyForecast]
 	MoonCache           MasterCache[types.Moon]
+	GeoCache            MasterCache[types.Locations]
+	ReverseGeoCache     MasterCache[types.Locations]
+	ResolveCache        MasterCache[types.Locations]
 }
 
 func InitMasterCache() *MasterCaches {
@@ -43,6 +46,9 @@ func InitMasterCache() *MasterCaches {
 		DailyForecastCache:  MasterCache[types.DailyForecast]{Data: make(map[string]CacheEntity[types.DailyForecast])},
 		HourlyForecastCache: MasterCache[types.HourlyForecast]{Data: make(map[string]CacheEntity[types.HourlyForecast])},
 		MoonCache:           MasterCache[types.Moon]{Data: make(map[string]CacheEntity[types.Moon])},
+		GeoCache:            MasterCache[types.Locations]{Data: make(map[string]CacheEntity[types.Locations])},
+		ReverseGeoCache:     MasterCache[types.Locations]{Data: make(map[string]CacheEntity[types.Locations])},
+		ResolveCache:        MasterCache[types.Locations]{Data: make(map[string]CacheEntity[types.Locations])},
 	}
 }
 
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Readings older than this window are useless to compute the pressure tendency
+const PressureRetention = 24 * time.Hour
+
+// pressure cache data type, representing a mapping between a location and its pressure history
+type PressureCache struct {
+	mu sync.RWMutex
+	db map[string][]types.PressureElement
+}
+
+func InitPressureCache() *PressureCache {
+	return &PressureCache{
+		db: make(map[string][]types.PressureElement),
+	}
+}
+
+func (cache *PressureCache) AddReading(cityName string, pressure float64, timestamp time.Time) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Drop readings that fall outside the retention window
+	threshold := timestamp.Add(-PressureRetention)
+	readings := slices.DeleteFunc(cache.db[cityName], func(reading types.PressureElement) bool {
+		return reading.Timestamp.Before(threshold)
+	})
+
+	cache.db[cityName] = append(readings, types.PressureElement{
+		Pressure:  pressure,
+		Timestamp: timestamp,
+	})
+}
+
+func (cache *PressureCache) GetCityReadings(cityName string) []types.PressureElement {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	// Return a copy so that callers cannot mutate the history
+	return slices.Clone(cache.db[cityName])
+}
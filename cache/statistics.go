@@ -2,6 +2,7 @@ package cache
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,13 @@ func (cache *StatCache) AddStatistic(cityName string, statDate string, dailyTemp
 	cache.db[key] = dailyTemp
 }
 
+// Splits a '<DATE>@<LOCATION>' key into its date and location
+func splitKey(key string) (string, string) {
+	statDate, cityName, _ := strings.Cut(key, "@")
+
+	return statDate, cityName
+}
+
 func (cache *StatCache) IsKeyInvalid(key string) bool {
 	cache.mu.RLock()
 	defer cache.mu.RUnlock()
@@ -45,12 +53,13 @@ func (cache *StatCache) IsKeyInvalid(key string) bool {
 
 	var validEntries uint = 0
 	for storedKey := range cache.db {
-		if !strings.HasSuffix(storedKey, key) {
+		statDate, cityName := splitKey(storedKey)
+		if cityName != key {
 			continue
 		}
 
 		// Get <DATE> from <DATE>@<LOCATION>
-		keyDate, err := time.Parse("2006-01-02", strings.Split(storedKey, "@")[0])
+		keyDate, err := time.Parse("2006-01-02", statDate)
 		if err != nil {
 			keyDate = time.Now() // Add a fallback date if parsing fails
 		}
@@ -75,9 +84,10 @@ func (cache *StatCache) GetCityStatistics(cityName string) []types.StatElement {
 	result := make([]types.StatElement, 0)
 
 	for key, record := range cache.db {
-		if strings.HasSuffix(key, cityName) {
+		statDate, statCity := splitKey(key)
+		if statCity == cityName {
 			// Get <DATE> from <DATE>@<LOCATION>
-			keyDate, err := time.Parse("2006-01-02", strings.Split(key, "@")[0])
+			keyDate, err := time.Parse("2006-01-02", statDate)
 			if err != nil {
 				keyDate = time.Now() // Add a fallback date if parsing fails
 			}
@@ -91,3 +101,104 @@ func (cache *StatCache) GetCityStatistics(cityName string) []types.StatElement {
 
 	return result
 }
+
+func (cache *StatCache) MergeStatistic(cityName string, statDate string, dailyTemp float64, overwrite bool) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Format key as '<DATE>@<LOCATION>
+	key := fmt.Sprintf("%s@%s", statDate, cityName)
+
+	// Existing records are preserved unless explicitly requested
+	if _, exists := cache.db[key]; exists && !overwrite {
+		return false
+	}
+
+	cache.db[key] = dailyTemp
+
+	return true
+}
+
+func (cache *StatCache) GetRecords(cityName string) []types.StatRecord {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	result := make([]types.StatRecord, 0)
+
+	// An empty city name selects the records of every location
+	for key, record := range cache.db {
+		statDate, statCity := splitKey(key)
+		if cityName != "" && statCity != cityName {
+			continue
+		}
+
+		result = append(result, types.StatRecord{
+			City:        statCity,
+			Date:        statDate,
+			Temperature: record,
+		})
+	}
+
+	// Sort records by location and then by date
+	slices.SortFunc(result, func(a, b types.StatRecord) int {
+		if cmp := strings.Compare(a.City, b.City); cmp != 0 {
+			return cmp
+		}
+
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	return result
+}
+
+// Returns the locations of the database, sorted by key
+func (cache *StatCache) GetLocations() []string {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	locations := make([]string, 0)
+	for key := range cache.db {
+		if _, cityName := splitKey(key); !seen[cityName] {
+			seen[cityName] = true
+			locations = append(locations, cityName)
+		}
+	}
+
+	slices.Sort(locations)
+
+	return locations
+}
+
+// Moves the records of a location to another one(e.g. from a legacy key to a canonical one).
+// Existing records of the target location take precedence over the moved ones, which are dropped.
+// Returns the number of merged and dropped records
+func (cache *StatCache) RenameLocation(from string, to string) (int, int) {
+	if from == to {
+		return 0, 0
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	var merged, dropped int
+	for key, record := range cache.db {
+		statDate, cityName := splitKey(key)
+		if cityName != from {
+			continue
+		}
+
+		delete(cache.db, key)
+
+		target := fmt.Sprintf("%s@%s", statDate, to)
+		if _, exists := cache.db[target]; exists {
+			dropped++
+			continue
+		}
+
+		cache.db[target] = record
+		merged++
+	}
+
+	return merged, dropped
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGetLocations(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Records  map[string]float64
+		Expected []string
+	}{
+		{"Empty", map[string]float64{}, []string{}},
+		{"Single location", map[string]float64{
+			"2025-05-28@45.46,9.19": 22.4,
+			"2025-05-29@45.46,9.19": 23.1,
+		}, []string{"45.46,9.19"}},
+		{"Sorted", map[string]float64{
+			"2025-05-28@MILAN":       22.4,
+			"2025-05-28@45.46,9.19":  22.4,
+			"2025-05-28@41.89,12.48": 25.0,
+		}, []string{"41.89,12.48", "45.46,9.19", "MILAN"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cache := InitStatCache()
+			maps.Copy(cache.db, test.Records)
+
+			got := cache.GetLocations()
+			if !slices.Equal(got, test.Expected) {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestRenameLocation(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Records  map[string]float64
+		From     string
+		To       string
+		Merged   int
+		Dropped  int
+		Expected map[string]float64
+	}{
+		{
+			"Move",
+			map[string]float64{"2025-05-28@MILAN": 22.4, "2025-05-29@MILAN": 23.1, "2025-05-28@ROME": 25.0},
+			"MILAN", "45.46,9.19", 2, 0,
+			map[string]float64{"2025-05-28@45.46,9.19": 22.4, "2025-05-29@45.46,9.19": 23.1, "2025-05-28@ROME": 25.0},
+		},
+		{
+			"Conflict",
+			map[string]float64{"2025-05-28@MILAN": 22.4, "2025-05-29@MILAN": 23.1, "2025-05-28@45.46,9.19": 21.0},
+			"MILAN", "45.46,9.19", 1, 1,
+			map[string]float64{"2025-05-28@45.46,9.19": 21.0, "2025-05-29@45.46,9.19": 23.1},
+		},
+		{
+			"Same location",
+			map[string]float64{"2025-05-28@45.46,9.19": 22.4},
+			"45.46,9.19", "45.46,9.19", 0, 0,
+			map[string]float64{"2025-05-28@45.46,9.19": 22.4},
+		},
+		{
+			"Unknown location",
+			map[string]float64{"2025-05-28@ROME": 25.0},
+			"MILAN", "45.46,9.19", 0, 0,
+			map[string]float64{"2025-05-28@ROME": 25.0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cache := InitStatCache()
+			maps.Copy(cache.db, test.Records)
+
+			merged, dropped := cache.RenameLocation(test.From, test.To)
+			if merged != test.Merged || dropped != test.Dropped {
+				t.Errorf("Got (%d, %d), wanted (%d, %d)", merged, dropped, test.Merged, test.Dropped)
+			}
+
+			if !maps.Equal(cache.db, test.Expected) {
+				t.Errorf("Got %v, wanted %v", cache.db, test.Expected)
+			}
+		})
+	}
+}
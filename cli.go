@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const cliUsage = `Usage:
+  zephyr                                     start the web service
+  zephyr export [flags]                      export the statistics database
+  zephyr import [flags] <file>               import records into the statistics database
+  zephyr migrate [flags]                     rename the legacy keys of the statistics database
+
+The subcommands talk to a running instance through its admin endpoints.
+Run 'zephyr <subcommand> -h' for the list of flags.
+`
+
+// Parses the common flags of the admin subcommands
+func adminFlags(fs *flag.FlagSet) (*string, *string) {
+	port := os.Getenv("ZEPHYR_PORT")
+	if port == "" {
+		port = "3000"
+	}
+
+	server := fs.String("server", "http://127.0.0.1:"+port, "address of the Zephyr instance")
+	token := fs.String("token", os.Getenv("ZEPHYR_ADMIN_TOKEN"), "admin token (default: $ZEPHYR_ADMIN_TOKEN)")
+
+	return server, token
+}
+
+func adminRequest(method, server, path string, params url.Values, token string, body io.Reader) (*http.Response, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(server, "/") + path)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(method, endpoint.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+
+		var errRes map[string]string
+		if err := json.NewDecoder(res.Body).Decode(&errRes); err == nil && errRes["error"] != "" {
+			return nil, errors.New(errRes["error"])
+		}
+
+		return nil, fmt.Errorf("unexpected status: %s", res.Status)
+	}
+
+	return res, nil
+}
+
+func exportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	server, token := adminFlags(fs)
+	city := fs.String("city", "", "export a single city (default: every city)")
+	format := fs.String("format", "csv", "output format, either 'csv' or 'ndjson'")
+	output := fs.String("o", "", "output file (default: standard output)")
+	fs.Parse(args)
+
+	params := url.Values{}
+	params.Set("format", *format)
+	if *city != "" {
+		params.Set("city", *city)
+	}
+
+	res, err := adminRequest(http.MethodGet, *server, "/admin/stats/export", params, *token, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	_, err = io.Copy(out, res.Body)
+	return err
+}
+
+func importCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	server, token := adminFlags(fs)
+	format := fs.String("format", "", "input format, either 'csv' or 'ndjson' (default: from file extension)")
+	overwrite := fs.Bool("overwrite", false, "replace existing records")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("specify the file to import")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Infer the format from the file extension if not specified
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(fs.Arg(0)), ".")
+	}
+
+	params := url.Values{}
+	params.Set("format", *format)
+	if *overwrite {
+		params.Set("overwrite", "")
+	}
+
+	res, err := adminRequest(http.MethodPost, *server, "/admin/stats/import", params, *token, file)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var summary map[string]int
+	if err := json.NewDecoder(res.Body).Decode(&summary); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d records, skipped %d existing records, %d records could not be resolved\n",
+		summary["imported"], summary["skipped"], summary["failed"])
+
+	return nil
+}
+
+func migrateCommand(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	server, token := adminFlags(fs)
+	fs.Parse(args)
+
+	res, err := adminRequest(http.MethodPost, *server, "/admin/stats/migrate", url.Values{}, *token, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var summary map[string]int
+	if err := json.NewDecoder(res.Body).Decode(&summary); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrated %d locations(%d records merged, %d conflicting records dropped), %d locations could not be resolved\n",
+		summary["migrated"], summary["merged"], summary["dropped"], summary["failed"])
+
+	return nil
+}
+
+// Runs a subcommand and returns the process exit code
+func runCommand(args []string) int {
+	var err error
+
+	switch args[0] {
+	case "export":
+		err = exportCommand(args[1:])
+	case "import":
+		err = importCommand(args[1:])
+	case "migrate":
+		err = migrateCommand(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(cliUsage)
+		return 0
+	default:
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zephyr %s: %s\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,220 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format, representing the encoding of a response
+type Format string
+
+const (
+	JSON        Format = "json"
+	XML         Format = "xml"
+	CSV         Format = "csv"
+	MessagePack Format = "msgpack"
+)
+
+// Media types of each format, the first one is used in responses
+var mediaTypes = map[Format][]string{
+	JSON:        {"application/json"},
+	XML:         {"application/xml", "text/xml"},
+	CSV:         {"text/csv"},
+	MessagePack: {"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+}
+
+// Tabular is implemented by the values whose CSV representation is a series
+// of rows(e.g. the entries of a forecast), stored in the member named by RowsKey
+type Tabular interface {
+	RowsKey() string
+}
+
+func Parse(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case JSON:
+		return JSON, nil
+	case XML:
+		return XML, nil
+	case CSV:
+		return CSV, nil
+	case MessagePack:
+		return MessagePack, nil
+	}
+
+	return "", fmt.Errorf("unsupported format '%s', use one of 'json', 'xml', 'csv' or 'msgpack'", format)
+}
+
+// Returns the supported format with the highest weight('q' parameter) in the Accept header
+// along with a flag indicating whether it was explicitly requested(i.e. not through a wildcard).
+// Formats with the same weight are picked in header order, while a zero weight excludes a format
+func FromAccept(accept string) (Format, bool) {
+	best, bestWeight := JSON, 0.0
+	for _, entry := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(entry, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		weight := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			if qValue, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsedWeight, err := strconv.ParseFloat(qValue, 64); err == nil {
+					weight = parsedWeight
+				}
+			}
+		}
+
+		if weight <= bestWeight {
+			continue
+		}
+
+		for format, values := range mediaTypes {
+			if slices.Contains(values, mediaType) {
+				best, bestWeight = format, weight
+			}
+		}
+	}
+
+	return best, bestWeight > 0
+}
+
+// Selects the format of a response
+//
+// The 'format' parameter takes precedence over the Accept header.
+// JSON is the default format
+func Negotiate(format string, accept string) (Format, error) {
+	if format != "" {
+		return Parse(format)
+	}
+
+	negotiated, _ := FromAccept(accept)
+
+	return negotiated, nil
+}
+
+func (format Format) ContentType() string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XML:
+		return "application/xml; charset=utf-8"
+	}
+
+	return mediaTypes[format][0]
+}
+
+// Encodes a value according to the format. Values are first converted to JSON, hence
+// every format uses the same field names(and the same representation of dates)
+func Encode(w io.Writer, val any, format Format) error {
+	return EncodeFields(w, val, format, nil)
+}
+
+// Encodes the selected fields of a value according to the format(see Encode),
+// nil fields select the whole value
+func EncodeFields(w io.Writer, val any, format Format, fields Fields) error {
+	if format == JSON && fields == nil {
+		return json.NewEncoder(w).Encode(val)
+	}
+
+	tree, err := toTree(val)
+	if err != nil {
+		return err
+	}
+
+	tree, _ = fields.prune(tree)
+
+	// Tabular values are encoded as a series of rows in CSV
+	if tabular, ok := val.(Tabular); ok && format == CSV {
+		node, _ := tree.(object)
+		for _, entry := range node {
+			if entry.key == tabular.RowsKey() {
+				tree = entry.value
+			}
+		}
+	}
+
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(tree)
+	case XML:
+		return encodeXML(w, tree)
+	case CSV:
+		return encodeCSV(w, tree)
+	case MessagePack:
+		return encodeMessagePack(w, tree)
+	}
+
+	return fmt.Errorf("unsupported format '%s'", format)
+}
+
+// member, representing a key-value pair of an object
+type member struct {
+	key   string
+	value any
+}
+
+// object, representing a JSON object whose keys
+// preserve the order of the struct fields
+type object []member
+
+// Converts a value to a tree made of object, []any, string, json.Number, bool and nil nodes
+func toTree(val any) (any, error) {
+	encoded, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	return decodeNode(decoder)
+}
+
+func decodeNode(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		node := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			node = append(node, member{key.(string), value})
+		}
+
+		// Consume the closing delimiter
+		_, err := decoder.Token()
+
+		return node, err
+	case json.Delim('['):
+		node := []any{}
+		for decoder.More() {
+			value, err := decodeNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			node = append(node, value)
+		}
+
+		_, err := decoder.Token()
+
+		return node, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,164 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testWind struct {
+	Direction string  `json:"direction"`
+	Speed     float64 `json:"speed"`
+}
+
+type testEntity struct {
+	Date      string   `json:"date"`
+	Condition string   `json:"condition"`
+	Wind      testWind `json:"wind"`
+}
+
+type testForecast struct {
+	Timezone string       `json:"timezone"`
+	Forecast []testEntity `json:"forecast"`
+}
+
+func (forecast testForecast) RowsKey() string { return "forecast" }
+
+var forecast = testForecast{
+	Timezone: "Europe/Rome",
+	Forecast: []testEntity{
+		{"2025-08-29", "Rain", testWind{"SSW", 4.08}},
+		{"2025-08-30", "Clear & Sunny", testWind{"N", 2}},
+	},
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Format   string
+		Accept   string
+		Expected Format
+	}{
+		{"Default format", "", "", JSON},
+		{"Wildcard", "", "*/*", JSON},
+		{"Parameter", "msgpack", "application/xml", MessagePack},
+		{"Header", "", "text/html, text/csv;q=0.9", CSV},
+		{"Header alias", "", "text/xml", XML},
+		{"Weights", "", "application/xml;q=0.1, text/csv;q=0.9", CSV},
+		{"Same weight", "", "text/csv, application/xml", CSV},
+		{"Not acceptable", "", "application/xml;q=0, */*", JSON},
+		{"Media type parameters", "", "application/xml;charset=utf-8;q=0.2, application/msgpack;q=0.5", MessagePack},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := Negotiate(test.Format, test.Accept)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+
+	if _, err := Negotiate("yaml", ""); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Format   Format
+		Value    any
+		Expected string
+	}{
+		{
+			"CSV rows",
+			CSV,
+			forecast,
+			"date,condition,wind.direction,wind.speed\n2025-08-29,Rain,SSW,4.08\n2025-08-30,Clear & Sunny,N,2\n",
+		},
+		{
+			"CSV single row",
+			CSV,
+			map[string]string{"error": "specify city name"},
+			"error\nspecify city name\n",
+		},
+		{
+			"XML",
+			XML,
+			forecast,
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				"<response><timezone>Europe/Rome</timezone><forecast>" +
+				"<item><date>2025-08-29</date><condition>Rain</condition><wind><direction>SSW</direction><speed>4.08</speed></wind></item>" +
+				"<item><date>2025-08-30</date><condition>Clear &amp; Sunny</condition><wind><direction>N</direction><speed>2</speed></wind></item>" +
+				"</forecast></response>\n",
+		},
+		{
+			"MessagePack",
+			MessagePack,
+			testWind{"N", 2.5},
+			"\x82\xa9direction\xa1N\xa5speed\xcb\x40\x04\x00\x00\x00\x00\x00\x00",
+		},
+		{
+			"MessagePack scalars",
+			MessagePack,
+			[]any{nil, true, -3, 300, "ok"},
+			"\x95\xc0\xc3\xfd\xd3\x00\x00\x00\x00\x00\x00\x01\x2c\xa2ok",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, test.Value, test.Format); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := buf.String(); got != test.Expected {
+				t.Errorf("Got %q, wanted %q", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestEncodeFields(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Format   Format
+		Fields   string
+		Expected string
+	}{
+		{"Top-level member", JSON, "timezone", `{"timezone":"Europe/Rome"}` + "\n"},
+		{"Nested path", JSON, "forecast.wind.speed", `{"forecast":[{"wind":{"speed":4.08}},{"wind":{"speed":2}}]}` + "\n"},
+		{"Overlapping paths", JSON, "forecast.date,forecast", `{"forecast":[{"date":"2025-08-29","condition":"Rain","wind":{"direction":"SSW","speed":4.08}},{"date":"2025-08-30","condition":"Clear \u0026 Sunny","wind":{"direction":"N","speed":2}}]}` + "\n"},
+		{"Unknown fields", JSON, "timezone.name,unknown", `{}` + "\n"},
+		{"CSV rows", CSV, "forecast.date, forecast.condition", "date,condition\n2025-08-29,Rain\n2025-08-30,Clear & Sunny\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fields, err := ParseFields(test.Fields)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := EncodeFields(&buf, forecast, test.Format, fields); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := buf.String(); got != test.Expected {
+				t.Errorf("Got %q, wanted %q", got, test.Expected)
+			}
+		})
+	}
+
+	for _, fields := range []string{"", " , ", "forecast..date", ".timezone"} {
+		if _, err := ParseFields(fields); err == nil {
+			t.Errorf("Expected an error for fields '%s'", fields)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package codec
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Flattens a node into a row, nested keys are joined by a dot(e.g. 'wind.speed')
+// while array elements are identified by their index(e.g. 'alerts.0.event')
+func flatten(prefix string, node any, row map[string]string, columns *[]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+
+		return prefix + "." + key
+	}
+
+	set := func(value string) {
+		if _, found := row[prefix]; !found {
+			*columns = append(*columns, prefix)
+		}
+
+		row[prefix] = value
+	}
+
+	switch val := node.(type) {
+	case object:
+		for _, field := range val {
+			flatten(join(field.key), field.value, row, columns)
+		}
+	case []any:
+		for idx, item := range val {
+			flatten(join(strconv.Itoa(idx)), item, row, columns)
+		}
+	case string:
+		set(val)
+	case json.Number:
+		set(val.String())
+	case bool:
+		set(strconv.FormatBool(val))
+	case nil:
+		set("")
+	}
+}
+
+func encodeCSV(w io.Writer, tree any) error {
+	// Arrays are encoded as a series of rows, any other value as a single row
+	items, isArray := tree.([]any)
+	if !isArray {
+		items = []any{tree}
+	}
+
+	// The header is the union of the columns of each row
+	var columns []string
+	seen := make(map[string]bool)
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		row := make(map[string]string)
+		var rowColumns []string
+
+		flatten("", item, row, &rowColumns)
+		for _, column := range rowColumns {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	// Scalar values have no key, name the column 'value'
+	header := make([]string, len(columns))
+	for idx, column := range columns {
+		header[idx] = column
+		if column == "" {
+			header[idx] = "value"
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for idx, column := range columns {
+			record[idx] = row[column]
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,124 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fields, representing the (nested) members to keep in a response. A nil
+// value keeps the whole member, while a non-nil one selects its members
+type Fields map[string]Fields
+
+// Parses a comma-separated list of dot-separated paths(e.g. 'timezone,forecast.max')
+func ParseFields(fields string) (Fields, error) {
+	parsed := Fields{}
+
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		keys := strings.Split(field, ".")
+		if slices.Contains(keys, "") {
+			return nil, fmt.Errorf("invalid field '%s'", field)
+		}
+
+		node := parsed
+		for idx, key := range keys {
+			child, found := node[key]
+			if found && child == nil {
+				break // The whole member is already selected
+			}
+
+			if idx == len(keys)-1 {
+				node[key] = nil
+				break
+			}
+
+			if !found {
+				child = Fields{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("specify at least one field")
+	}
+
+	return parsed, nil
+}
+
+// Removes the members not selected by the fields from a tree. Fields are applied
+// to each element of an array, while unknown fields are ignored
+func (fields Fields) prune(node any) (any, bool) {
+	if fields == nil {
+		return node, true
+	}
+
+	switch val := node.(type) {
+	case object:
+		pruned := object{}
+		for _, entry := range val {
+			child, found := fields[entry.key]
+			if !found {
+				continue
+			}
+
+			if value, keep := child.prune(entry.value); keep {
+				pruned = append(pruned, member{entry.key, value})
+			}
+		}
+
+		return pruned, true
+	case []any:
+		pruned := make([]any, 0, len(val))
+		for _, item := range val {
+			if value, keep := fields.prune(item); keep {
+				pruned = append(pruned, value)
+			}
+		}
+
+		return pruned, true
+	case nil:
+		// Keep missing objects(e.g. a null pressure tendency)
+		return nil, true
+	}
+
+	// Scalars have no members
+	return nil, false
+}
+
+func (node object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for idx, entry := range node {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(entry.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
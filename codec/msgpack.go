@@ -0,0 +1,87 @@
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// MessagePack encoder, see https://github.com/msgpack/msgpack/blob/master/spec.md
+
+func writeLength(w *bufio.Writer, length int, fix byte, fixMax int, codes [3]byte) {
+	switch {
+	case length <= fixMax:
+		w.WriteByte(fix | byte(length))
+	case codes[0] != 0 && length <= math.MaxUint8:
+		w.WriteByte(codes[0])
+		w.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		w.WriteByte(codes[1])
+		binary.Write(w, binary.BigEndian, uint16(length))
+	default:
+		w.WriteByte(codes[2])
+		binary.Write(w, binary.BigEndian, uint32(length))
+	}
+}
+
+func writeString(w *bufio.Writer, val string) {
+	writeLength(w, len(val), 0xa0, 31, [3]byte{0xd9, 0xda, 0xdb})
+	w.WriteString(val)
+}
+
+func writeNumber(w *bufio.Writer, val json.Number) {
+	if integer, err := val.Int64(); err == nil {
+		switch {
+		case integer >= 0 && integer <= 127:
+			w.WriteByte(byte(integer)) // Positive fixint
+		case integer < 0 && integer >= -32:
+			w.WriteByte(byte(int8(integer))) // Negative fixint
+		default:
+			w.WriteByte(0xd3) // int 64
+			binary.Write(w, binary.BigEndian, integer)
+		}
+
+		return
+	}
+
+	float, _ := val.Float64()
+	w.WriteByte(0xcb) // float 64
+	binary.Write(w, binary.BigEndian, math.Float64bits(float))
+}
+
+func writeNode(w *bufio.Writer, node any) {
+	switch val := node.(type) {
+	case object:
+		writeLength(w, len(val), 0x80, 15, [3]byte{0, 0xde, 0xdf})
+		for _, field := range val {
+			writeString(w, field.key)
+			writeNode(w, field.value)
+		}
+	case []any:
+		writeLength(w, len(val), 0x90, 15, [3]byte{0, 0xdc, 0xdd})
+		for _, item := range val {
+			writeNode(w, item)
+		}
+	case string:
+		writeString(w, val)
+	case json.Number:
+		writeNumber(w, val)
+	case bool:
+		if val {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+	default:
+		w.WriteByte(0xc0) // nil
+	}
+}
+
+func encodeMessagePack(w io.Writer, tree any) error {
+	writer := bufio.NewWriter(w)
+	writeNode(writer, tree)
+
+	return writer.Flush()
+}
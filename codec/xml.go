@@ -0,0 +1,79 @@
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Returns a valid XML element name from an object key
+func elementName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+
+		return '_'
+	}, key)
+
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) && name[0] != '_' {
+		name = "_" + name
+	}
+
+	return name
+}
+
+func writeElement(w io.Writer, name string, node any) error {
+	if _, err := io.WriteString(w, "<"+name+">"); err != nil {
+		return err
+	}
+
+	switch val := node.(type) {
+	case object:
+		for _, field := range val {
+			if err := writeElement(w, elementName(field.key), field.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		// Array elements are wrapped in 'item' elements
+		for _, item := range val {
+			if err := writeElement(w, "item", item); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := xml.EscapeText(w, []byte(val)); err != nil {
+			return err
+		}
+	case json.Number:
+		if _, err := io.WriteString(w, val.String()); err != nil {
+			return err
+		}
+	case bool:
+		if _, err := io.WriteString(w, strconv.FormatBool(val)); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "</"+name+">")
+
+	return err
+}
+
+func encodeXML(w io.Writer, tree any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	if err := writeElement(w, "response", tree); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
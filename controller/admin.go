@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/codec"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Maximum size of an imported file(32 MiB)
+const maxImportSize = 32 << 20
+
+// Maximum number of cities of an imported file that are geocoded
+const maxImportCities = 100
+
+// Maximum size of an alias request(4 KiB)
+const maxAliasRequestSize = 4 << 10
+
+// The 'format' parameter of the admin endpoints selects the exchange format,
+// hence their responses are negotiated through the Accept header only
+func getAdminFormat(req *http.Request) codec.Format {
+	format, _ := codec.FromAccept(req.Header.Get("Accept"))
+
+	return format
+}
+
+func checkAdmin(res http.ResponseWriter, req *http.Request, vars *types.Variables) bool {
+	// Admin endpoints are disabled unless an admin token is configured
+	if vars.AdminToken == "" {
+		encodeError(res, getAdminFormat(req), "error", "admin endpoints are disabled", http.StatusForbidden)
+		return false
+	}
+
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(vars.AdminToken)) != 1 {
+		encodeError(res, getAdminFormat(req), "error", "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
+
+func ExportStatistics(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		encodeError(res, getAdminFormat(req), "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !checkAdmin(res, req, vars) {
+		return
+	}
+
+	format, err := model.ParseExchangeFormat(req.URL.Query().Get("format"))
+	if err != nil {
+		encodeError(res, getAdminFormat(req), "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Export a single city when the 'city' parameter is specified, every city otherwise
+	var cityKey string
+	if cityName := req.URL.Query().Get("city"); cityName != "" {
+		cityKey, err = newCityLocation(cityName, resolveCache, vars).key()
+		if err != nil {
+			encodeError(res, getAdminFormat(req), "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	records := statCache.GetRecords(cityKey)
+
+	contentType := "text/csv"
+	if format == model.NDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	res.Header().Set("Content-Type", contentType)
+	res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"statistics.%s\"", format))
+	res.WriteHeader(http.StatusOK)
+
+	// The status code is already sent, hence a failed export(e.g. a closed connection) can only be logged
+	if err := model.EncodeStatRecords(res, records, format); err != nil {
+		log.Printf("Cannot export the statistics: %v", err)
+	}
+}
+
+func ImportStatistics(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodPost {
+		encodeError(res, getAdminFormat(req), "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !checkAdmin(res, req, vars) {
+		return
+	}
+
+	format, err := model.ParseExchangeFormat(req.URL.Query().Get("format"))
+	if err != nil {
+		encodeError(res, getAdminFormat(req), "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check whether the 'overwrite' parameter(replace existing records) is specified
+	overwrite := req.URL.Query().Has("overwrite")
+
+	// Validate the whole file before touching the database
+	records, err := model.DecodeStatRecords(http.MaxBytesReader(res, req.Body, maxImportSize), format)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		encodeError(res, getAdminFormat(req), "error", fmt.Sprintf("file too large, the limit is %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err != nil {
+		encodeError(res, getAdminFormat(req), "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Cities identified by their coordinates are not geocoded, hence they do not count toward the limit
+	cityNames := make(map[string]bool)
+	for _, record := range records {
+		if !isCanonicalKey(record.City) {
+			cityNames[record.City] = true
+		}
+	}
+
+	if len(cityNames) > maxImportCities {
+		encodeError(res, getAdminFormat(req), "error", fmt.Sprintf("too many cities to geocode, the limit is %d", maxImportCities), http.StatusBadRequest)
+		return
+	}
+
+	// Resolve each city once, records of the cities that cannot be resolved are not imported
+	cityKeys := make(map[string]string)
+	for _, record := range records {
+		if _, found := cityKeys[record.City]; found {
+			continue
+		}
+
+		// An empty key marks a city that cannot be resolved
+		cityKeys[record.City], _ = getCanonicalKey(record.City, resolveCache, vars)
+	}
+
+	var imported, skipped, failed int
+	for _, record := range records {
+		cityKey := cityKeys[record.City]
+		switch {
+		case cityKey == "":
+			failed++
+		case statCache.MergeStatistic(cityKey, record.Date, record.Temperature, overwrite):
+			imported++
+		default:
+			skipped++
+		}
+	}
+
+	encodeValue(res, getAdminFormat(req), map[string]int{
+		"imported": imported,
+		"skipped":  skipped,
+		"failed":   failed,
+	}, nil)
+}
+
+// Checks whether a database key is canonical, that is, a pair of rounded coordinates
+func isCanonicalKey(key string) bool {
+	lat, lon, found := strings.Cut(key, ",")
+	if !found {
+		return false
+	}
+
+	parsedLat, latErr := strconv.ParseFloat(lat, 64)
+	parsedLon, lonErr := strconv.ParseFloat(lon, 64)
+
+	return latErr == nil && lonErr == nil && coordinatesKey(parsedLat, parsedLon) == key
+}
+
+// Returns the canonical key of a location of the database. Legacy keys are the
+// formatted city names(e.g. 'NEW+YORK'), which are geocoded again
+func getCanonicalKey(cityKey string, resolveCache *cache.MasterCache[types.Locations], vars *types.Variables) (string, error) {
+	if isCanonicalKey(cityKey) {
+		return cityKey, nil
+	}
+
+	return newCityLocation(strings.ReplaceAll(cityKey, "+", " "), resolveCache, vars).key()
+}
+
+func MigrateStatistics(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodPost {
+		encodeError(res, getAdminFormat(req), "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !checkAdmin(res, req, vars) {
+		return
+	}
+
+	var migrated, merged, dropped, failed int
+	for _, cityKey := range statCache.GetLocations() {
+		if isCanonicalKey(cityKey) {
+			continue
+		}
+
+		canonicalKey, err := getCanonicalKey(cityKey, resolveCache, vars)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		mergedRecords, droppedRecords := statCache.RenameLocation(cityKey, canonicalKey)
+		merged += mergedRecords
+		dropped += droppedRecords
+		migrated++
+	}
+
+	encodeValue(res, getAdminFormat(req), map[string]int{
+		"migrated": migrated,
+		"merged":   merged,
+		"dropped":  dropped,
+		"failed":   failed,
+	}, nil)
+}
+
+func ManageAliases(res http.ResponseWriter, req *http.Request, vars *types.Variables) {
+	if !checkAdmin(res, req, vars) {
+		return
+	}
+
+	format := getAdminFormat(req)
+
+	if vars.Aliases == nil {
+		encodeError(res, format, "error", "aliases are disabled", http.StatusNotImplemented)
+		return
+	}
+
+	// Extract the alias name from '/admin/aliases/:name'
+	name := strings.Trim(strings.TrimPrefix(req.URL.Path, "/admin/aliases"), "/")
+
+	switch req.Method {
+	case http.MethodGet:
+		if name == "" {
+			encodeValue(res, format, vars.Aliases.List(), nil)
+			return
+		}
+
+		alias, found := vars.Aliases.Get(name)
+		if !found {
+			encodeError(res, format, "error", "alias not found", http.StatusNotFound)
+			return
+		}
+
+		encodeValue(res, format, alias, nil)
+	case http.MethodPut:
+		if name == "" {
+			encodeError(res, format, "error", "specify alias name", http.StatusBadRequest)
+			return
+		}
+
+		// Parse '{"lat": 45.46, "lon": 9.19}' or '{"query": "Milan,IT"}'
+		var alias types.Alias
+		if err := json.NewDecoder(http.MaxBytesReader(res, req.Body, maxAliasRequestSize)).Decode(&alias); err != nil {
+			encodeError(res, format, "error", "invalid request body", http.StatusBadRequest)
+			return
+		}
+		alias.Name = name
+
+		if err := vars.Aliases.Set(alias); err != nil {
+			encodeError(res, format, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		alias, _ = vars.Aliases.Get(name)
+		encodeValue(res, format, alias, nil)
+	case http.MethodDelete:
+		if name == "" {
+			encodeError(res, format, "error", "specify alias name", http.StatusBadRequest)
+			return
+		}
+
+		deleted, err := vars.Aliases.Delete(name)
+		if err != nil {
+			encodeError(res, format, "error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if !deleted {
+			encodeError(res, format, "error", "alias not found", http.StatusNotFound)
+			return
+		}
+
+		encodeValue(res, format, map[string]string{"deleted": name}, nil)
+	default:
+		encodeError(res, format, "error", "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
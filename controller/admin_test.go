@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestIsCanonicalKey(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Key      string
+		Expected bool
+	}{
+		{"Canonical", "45.46,9.19", true},
+		{"Negative", "-33.87,-151.21", true},
+		{"Zero", "0.00,0.00", true},
+		{"Legacy name", "MILAN", false},
+		{"Legacy name with comma", "MILAN,IT", false},
+		{"Too many decimals", "45.464,9.19", false},
+		{"Too few decimals", "45.5,9.19", false},
+		{"Negative zero", "-0.00,0.00", false},
+		{"Spaces", "45.46, 9.19", false},
+		{"Empty", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := isCanonicalKey(test.Key)
+			if got != test.Expected {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestImportStatisticsSize(t *testing.T) {
+	vars := &types.Variables{AdminToken: "secret"}
+	body := strings.NewReader(strings.Repeat("MILAN,2025-05-28,22.4\n", maxImportSize/20))
+
+	req := httptest.NewRequest("POST", "/admin/stats/import", body)
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+
+	ImportStatistics(res, req, &cache.MasterCache[types.Locations]{}, cache.InitStatCache(), vars)
+	if res.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Got %d, wanted %d", res.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestImportStatisticsCities(t *testing.T) {
+	vars := &types.Variables{AdminToken: "secret"}
+
+	var names, coordinates strings.Builder
+	for idx := range maxImportCities + 1 {
+		fmt.Fprintf(&names, "CITY %d,2025-05-28,22.4\n", idx)
+		fmt.Fprintf(&coordinates, "%q,2025-05-28,22.4\n", coordinatesKey(float64(idx%90), 9.19))
+	}
+
+	tests := []struct {
+		Name     string
+		Body     string
+		Expected int
+	}{
+		{"Too many names", names.String(), http.StatusBadRequest},
+		{"Coordinates", coordinates.String(), http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/admin/stats/import", strings.NewReader(test.Body))
+			req.Header.Set("Authorization", "Bearer secret")
+			res := httptest.NewRecorder()
+
+			ImportStatistics(res, req, &cache.MasterCache[types.Locations]{}, cache.InitStatCache(), vars)
+			if res.Code != test.Expected {
+				t.Errorf("Got %d(%s), wanted %d", res.Code, res.Body.String(), test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+const (
+	// Maximum number of cities of a batch
+	maxBatchSize = 50
+	// Maximum number of cities retrieved concurrently
+	batchConcurrency = 4
+	// Maximum size of a batch request(64 KiB)
+	maxBatchRequestSize = 64 << 10
+)
+
+// Removes blank and duplicated city names, preserving their order
+func getCityNames(cityNames []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, cityName := range cityNames {
+		cityName = strings.TrimSpace(cityName)
+		if cityName == "" || seen[fmtKey(cityName)] {
+			continue
+		}
+
+		seen[fmtKey(cityName)] = true
+		names = append(names, cityName)
+	}
+
+	return names
+}
+
+// Retrieves the weather of multiple cities concurrently. Errors are
+// reported for each city rather than failing the whole batch
+func writeBatch(
+	res http.ResponseWriter,
+	req *http.Request,
+	cityNames []string,
+	cache *cache.MasterCache[types.Weather],
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	cityNames = getCityNames(cityNames)
+	if len(cityNames) == 0 {
+		writeError(res, req, "error", "specify at least one city", http.StatusBadRequest)
+		return
+	}
+
+	if len(cityNames) > maxBatchSize {
+		writeError(res, req, "error", fmt.Sprintf("too many cities, the limit is %d", maxBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the timezone from the 'tz' parameter
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batch := types.Batch{Results: make([]types.BatchResult, len(cityNames))}
+	semaphore := make(chan struct{}, batchConcurrency)
+
+	var wg sync.WaitGroup
+	for idx, cityName := range cityNames {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result := &batch.Results[idx]
+			result.City = cityName
+
+			weather, err := fetchWeather(newLocation(cityName, resolveCache, vars), lang, cache, statCache, true, vars)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+
+			localizeWeather(&weather, timezone, lang)
+			fmtWeather(&weather, unit)
+			result.Weather = &weather
+		})
+	}
+	wg.Wait()
+
+	writeValue(res, req, batch)
+}
+
+func GetWeatherBatch(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Weather],
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city names from '/weather?cities=a,b,c'
+	if !req.URL.Query().Has("cities") {
+		writeError(res, req, "error", "specify city name", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeBatch(res, req, strings.Split(req.URL.Query().Get("cities"), ","), cache, resolveCache, statCache, vars)
+}
+
+func PostBatch(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Weather],
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodPost {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city names from '{"cities": ["a", "b", "c"]}'
+	var body struct {
+		Cities []string `json:"cities"`
+	}
+
+	req.Body = http.MaxBytesReader(res, req.Body, maxBatchRequestSize)
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(res, req, "error", "invalid request body, use '{\"cities\": [...]}'", http.StatusBadRequest)
+		return
+	}
+
+	writeBatch(res, req, body.Cities, cache, resolveCache, statCache, vars)
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetCityNames(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    []string
+		Expected []string
+	}{
+		{"Unique", []string{"Milan", "Rome"}, []string{"Milan", "Rome"}},
+		{"Blanks", []string{"", " Milan ", "  "}, []string{"Milan"}},
+		{"Duplicates", []string{"Milan", "MILAN", " milan", "Rome"}, []string{"Milan", "Rome"}},
+		{"Spaces", []string{"New York", "new york"}, []string{"New York"}},
+		{"Order", []string{"Rome", "Milan", "Rome"}, []string{"Rome", "Milan"}},
+		{"Empty", []string{}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := getCityNames(test.Input)
+			if !slices.Equal(got, test.Expected) {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestWriteBatchSize(t *testing.T) {
+	tooMany := make([]string, maxBatchSize+1)
+	for idx := range tooMany {
+		tooMany[idx] = fmt.Sprintf("City %d", idx)
+	}
+
+	tests := []struct {
+		Name  string
+		Input []string
+	}{
+		{"No cities", []string{" ", ""}},
+		{"Too many cities", tooMany},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/batch", nil)
+
+			writeBatch(res, req, test.Input, &cache.MasterCache[types.Weather]{}, &cache.MasterCache[types.Locations]{}, nil, &types.Variables{})
+			if res.Code != http.StatusBadRequest {
+				t.Errorf("Got %d, wanted %d", res.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -1,9 +1,8 @@
 package controller
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"math/rand"
 	"net/http"
 	"strconv"
@@ -11,53 +10,69 @@ import (
 	"time"
 
 	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
 	"github.com/ceticamarco/zephyr/model"
 	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
 )
 
-func jsonError(res http.ResponseWriter, key string, value string, status int) {
-	res.Header().Set("Content-Type", "application/json")
-	res.WriteHeader(status)
-	json.NewEncoder(res).Encode(map[string]string{key: value})
-}
+func fmtTemperature(temp string, unit units.System) string {
+	parsedTemp, _ := strconv.ParseFloat(temp, 64)
 
-func jsonValue(res http.ResponseWriter, val any) {
-	res.Header().Set("Content-Type", "application/json")
-	res.WriteHeader(http.StatusOK)
-	json.NewEncoder(res).Encode(val)
+	return units.FormatTemperature(parsedTemp, unit.Temperature)
 }
 
-func fmtTemperature(temp string, isImperial bool) string {
+// Formats a temperature with two decimal digits, so that narrow
+// ranges(e.g. confidence intervals) are not rounded away
+func fmtTemperaturePrecise(temp string, unit units.System) string {
 	parsedTemp, _ := strconv.ParseFloat(temp, 64)
 
-	if isImperial {
-		return fmt.Sprintf("%d°F", int(math.Round(parsedTemp*(9/5)+32)))
-	}
-
-	return fmt.Sprintf("%d°C", int(math.Round(parsedTemp)))
+	return units.FormatTemperaturePrecise(parsedTemp, unit.Temperature, 2)
 }
 
-func fmtStdDev(stdDev string, isImperial bool) string {
+func fmtStdDev(stdDev string, unit units.System) string {
 	parsedStdDev, _ := strconv.ParseFloat(stdDev, 64)
 
-	if isImperial {
-		return fmt.Sprintf("%.4f°F", (parsedStdDev*(9/5) + 32))
-	}
+	return units.FormatTemperatureDelta(parsedStdDev, unit.Temperature, 4, false)
+}
+
+func fmtTemperatureDiff(diff string, unit units.System) string {
+	parsedDiff, _ := strconv.ParseFloat(diff, 64)
 
-	return fmt.Sprintf("%.4f°C", parsedStdDev)
+	return units.FormatTemperatureDelta(parsedDiff, unit.Temperature, 2, true)
 }
 
-func fmtWind(windSpeed string, isImperial bool) string {
-	// Convert wind speed to mph or km/s from m/s
-	// 1 m/s = 2.23694 mph
-	// 1 m/s = 3.6 km/h
+func fmtWind(windSpeed string, unit units.System) string {
+	// Wind speed is expressed in m/s
 	parsedSpeed, _ := strconv.ParseFloat(windSpeed, 64)
 
-	if isImperial {
-		return fmt.Sprintf("%.1f mph", (parsedSpeed * 2.23694))
+	return units.FormatSpeed(parsedSpeed, unit.Speed)
+}
+
+func fmtPressure(pressure string, unit units.System) string {
+	parsedPressure, _ := strconv.ParseFloat(pressure, 64)
+
+	return units.FormatPressure(parsedPressure, unit.Pressure)
+}
+
+func fmtVisibility(visibility string, unit units.System) string {
+	parsedVisibility, _ := strconv.ParseFloat(visibility, 64)
+
+	return units.FormatDistance(parsedVisibility, unit.Distance)
+}
+
+func getConfidence(req *http.Request) (float64, error) {
+	// Confidence level of the statistical estimates, 95% by default
+	if !req.URL.Query().Has("confidence") {
+		return 0.95, nil
+	}
+
+	confidence, err := strconv.ParseFloat(req.URL.Query().Get("confidence"), 64)
+	if err != nil || confidence <= 0 || confidence >= 1 {
+		return 0, errors.New("confidence level must be between 0 and 1")
 	}
 
-	return fmt.Sprintf("%.1f km/h", (parsedSpeed * 3.6))
+	return confidence, nil
 }
 
 func fmtKey(key string) string {
@@ -68,21 +83,42 @@ func fmtKey(key string) string {
 	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(key), " ", "+"))
 }
 
-func fmtDailyForecast(forecast *types.DailyForecast, isImperial bool) {
+func fmtDailyForecast(forecast *types.DailyForecast, unit units.System) {
 	for idx := range forecast.Forecast {
 		val := &forecast.Forecast[idx]
-		val.Min = fmtTemperature(val.Min, isImperial)
-		val.Max = fmtTemperature(val.Max, isImperial)
-		val.FeelsLike = fmtTemperature(val.FeelsLike, isImperial)
-		val.Wind.Speed = fmtWind(val.Wind.Speed, isImperial)
+		val.Min = fmtTemperature(val.Min, unit)
+		val.Max = fmtTemperature(val.Max, unit)
+		val.FeelsLike = fmtTemperature(val.FeelsLike, unit)
+		val.Wind.Speed = fmtWind(val.Wind.Speed, unit)
 	}
 }
 
-func fmtHourlyForecast(forecast *types.HourlyForecast, isImperial bool) {
+func fmtHourlyForecast(forecast *types.HourlyForecast, unit units.System) {
 	for idx := range forecast.Forecast {
 		val := &forecast.Forecast[idx]
-		val.Temperature = fmtTemperature(val.Temperature, isImperial)
-		val.Wind.Speed = fmtWind(val.Wind.Speed, isImperial)
+		val.Temperature = fmtTemperature(val.Temperature, unit)
+		val.Wind.Speed = fmtWind(val.Wind.Speed, unit)
+	}
+}
+
+func fmtStatistics(stats *types.StatResult, unit units.System) {
+	stats.Min = fmtTemperature(stats.Min, unit)
+	stats.Max = fmtTemperature(stats.Max, unit)
+	stats.Mean = fmtTemperature(stats.Mean, unit)
+	stats.StdDev = fmtStdDev(stats.StdDev, unit)
+	stats.Median = fmtTemperature(stats.Median, unit)
+	stats.Mode = fmtTemperature(stats.Mode, unit)
+	for _, ci := range []*types.ConfidenceInterval{stats.MeanCI, stats.MedianCI} {
+		if ci != nil {
+			ci.Level = fmt.Sprintf("%s%%", ci.Level)
+			ci.Lower = fmtTemperaturePrecise(ci.Lower, unit)
+			ci.Upper = fmtTemperaturePrecise(ci.Upper, unit)
+		}
+	}
+	if stats.Anomaly != nil {
+		for idx, val := range *stats.Anomaly {
+			(*stats.Anomaly)[idx].Temp = fmtTemperature(val.Temp, unit)
+		}
 	}
 }
 
@@ -92,180 +128,206 @@ func deepCopyForecast[T types.DailyForecast | types.HourlyForecast](original T)
 	switch any(original).(type) {
 	case types.DailyForecast:
 		orig := any(original).(types.DailyForecast)
-		fc_copy = any(types.DailyForecast{
-			Forecast: append([]types.DailyForecastEntity(nil), orig.Forecast...),
-		}).(T)
+		orig.Forecast = append([]types.DailyForecastEntity(nil), orig.Forecast...)
+		fc_copy = any(orig).(T)
 	case types.HourlyForecast:
 		orig := any(original).(types.HourlyForecast)
-		fc_copy = any(types.HourlyForecast{
-			Forecast: append([]types.HourlyForecastEntity(nil), orig.Forecast...),
-		}).(T)
+		orig.Forecast = append([]types.HourlyForecastEntity(nil), orig.Forecast...)
+		fc_copy = any(orig).(T)
 	}
 
 	return fc_copy
 }
 
-func GetWeather(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Weather], statCache *cache.StatCache, vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Extract city name from '/weather/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/weather/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+func fmtWeather(weather *types.Weather, unit units.System) {
+	weather.Temperature = fmtTemperature(weather.Temperature, unit)
+	weather.Min = fmtTemperature(weather.Min, unit)
+	weather.Max = fmtTemperature(weather.Max, unit)
+	weather.FeelsLike = fmtTemperature(weather.FeelsLike, unit)
+}
 
-	if cityName == "" {
-		jsonError(res, "error", "specify city name", http.StatusMethodNotAllowed)
-		return
+func fmtPressureTendency(tendency *types.PressureTendency, unit units.System) *types.PressureTendency {
+	if tendency == nil {
+		return nil
 	}
 
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	// Copy the tendency object to avoid mutating the cached value
+	parsedRate, _ := strconv.ParseFloat(tendency.Rate, 64)
+	fmtTendency := *tendency
+	fmtTendency.Rate = units.FormatPressureTendency(parsedRate, unit.Pressure)
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format weather object and then return it
-		cachedValue.Temperature = fmtTemperature(cachedValue.Temperature, isImperial)
-		cachedValue.Min = fmtTemperature(cachedValue.Min, isImperial)
-		cachedValue.Max = fmtTemperature(cachedValue.Max, isImperial)
-		cachedValue.FeelsLike = fmtTemperature(cachedValue.FeelsLike, isImperial)
+	return &fmtTendency
+}
 
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+func fmtMetrics(metrics *types.Metrics, unit units.System) {
+	metrics.Humidity = fmt.Sprintf("%s%%", metrics.Humidity)
+	metrics.Pressure = fmtPressure(metrics.Pressure, unit)
+	metrics.DewPoint = fmtTemperature(metrics.DewPoint, unit)
+	metrics.Visibility = fmtVisibility(metrics.Visibility, unit)
+	metrics.Tendency = fmtPressureTendency(metrics.Tendency, unit)
+}
 
-		// Get city weather
-		weather, dailyTemp, err := model.GetWeather(&city, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+func getCityName(req *http.Request, prefix string) string {
+	// Extract city name from '<prefix>:city'
+	path := strings.TrimPrefix(req.URL.Path, prefix)
 
-		// Add result to cache
-		cache.AddEntry(weather, fmtKey(cityName))
+	return strings.Trim(path, "/") // Remove trailing slash if present
+}
 
-		// Insert new statistic entry into the statistics database
-		currentDate := time.Now().Format("2006-01-02")
-		statCache.AddStatistic(fmtKey(cityName), currentDate, dailyTemp)
+func GetWeather(
+	res http.ResponseWriter,
+	req *http.Request,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		// Format weather object and then return it
-		weather.Temperature = fmtTemperature(weather.Temperature, isImperial)
-		weather.Min = fmtTemperature(weather.Min, isImperial)
-		weather.Max = fmtTemperature(weather.Max, isImperial)
-		weather.FeelsLike = fmtTemperature(weather.FeelsLike, isImperial)
+	// Extract city name from '/weather/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/weather/"), &masterCache.ResolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		jsonValue(res, weather)
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
 	}
-}
 
-func GetMetrics(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Metrics], vars *types.Variables) {
-	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+	// Retrieve the timezone from the 'tz' parameter
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Extract city name from '/metrics/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/metrics/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if cityName == "" {
-		jsonError(res, "error", "specify city name", http.StatusMethodNotAllowed)
+	weather, err := fetchWeather(loc, lang, &masterCache.WeatherCache, statCache, true, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	// Name the coordinates after the nearest place when the 'place' parameter is specified
+	place, err := getPlace(req, loc, &masterCache.ReverseGeoCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format metrics object and then return it
-		cachedValue.Humidity = fmt.Sprintf("%s%%", cachedValue.Humidity)
-		cachedValue.Pressure = fmt.Sprintf("%s hPa", cachedValue.Pressure)
-		cachedValue.DewPoint = fmtTemperature(cachedValue.DewPoint, isImperial)
-		cachedValue.Visibility = fmt.Sprintf("%skm", cachedValue.Visibility)
+	if place != nil {
+		weather.Location = place
+	}
 
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	localizeWeather(&weather, timezone, lang)
 
-		// Get city weather
-		metrics, err := model.GetMetrics(&city, vars.Token)
+	// Check whether the 'format' parameter is a template(text mode) rather than an encoding
+	if format := req.URL.Query().Get("format"); format != "" && !isEncoding(format) {
+		placeholders := weatherPlaceholders(loc, weather, unit, lang, masterCache, pressureCache, vars)
+		text, err := renderFormat(format, placeholders)
 		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		// Add result to cache
-		cache.AddEntry(metrics, fmtKey(cityName))
+		textValue(res, text)
+		return
+	}
 
-		// Format metrics object and then return it
-		metrics.Humidity = fmt.Sprintf("%s%%", metrics.Humidity)
-		metrics.Pressure = fmt.Sprintf("%s hPa", metrics.Pressure)
-		metrics.DewPoint = fmtTemperature(metrics.DewPoint, isImperial)
-		metrics.Visibility = fmt.Sprintf("%skm", metrics.Visibility)
+	// Format weather object and then return it
+	fmtWeather(&weather, unit)
 
-		jsonValue(res, metrics)
-	}
+	writeValue(res, req, weather)
 }
 
-func GetWind(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Wind], vars *types.Variables) {
+func GetMetrics(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Metrics],
+	resolveCache *cache.MasterCache[types.Locations],
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) {
 	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract city name from '/wind/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/wind/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+	// Extract city name from '/metrics/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/metrics/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if cityName == "" {
-		jsonError(res, "error", "specify city name", http.StatusMethodNotAllowed)
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	metrics, err := fetchMetrics(loc, cache, pressureCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	cachedValue, found := cache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-	if found {
-		// Format wind object and then return it
-		cachedValue.Speed = fmtWind(cachedValue.Speed, isImperial)
+	// Format metrics object and then return it
+	fmtMetrics(&metrics, unit)
 
-		jsonValue(res, cachedValue)
-	} else {
-		// Get city coordinates
-		city, err := model.GetCoordinates(cityName, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	writeValue(res, req, metrics)
+}
 
-		// Get city wind
-		wind, err := model.GetWind(&city, vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+func GetWind(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Wind],
+	resolveCache *cache.MasterCache[types.Locations],
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		// Add result to cache
-		cache.AddEntry(wind, fmtKey(cityName))
+	// Extract city name from '/wind/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/wind/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		// Format wind object and then return it
-		wind.Speed = fmtWind(wind.Speed, isImperial)
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		jsonValue(res, wind)
+	wind, err := fetchWind(loc, cache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
 	}
+
+	// Format wind object and then return it
+	wind.Speed = fmtWind(wind.Speed, unit)
+
+	writeValue(res, req, wind)
 }
 
 func GetForecast(
@@ -273,105 +335,103 @@ func GetForecast(
 	req *http.Request,
 	dCache *cache.MasterCache[types.DailyForecast],
 	hCache *cache.MasterCache[types.HourlyForecast],
+	resolveCache *cache.MasterCache[types.Locations],
 	vars *types.Variables,
 ) {
 	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract city name from '/forecast/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/forecast/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+	// Extract city name from '/forecast/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/forecast/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the timezone from the 'tz' parameter
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if cityName == "" {
-		jsonError(res, "error", "specify city name", http.StatusMethodNotAllowed)
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	// Render the forecast as a table for terminal clients
+	table := wantsTable(req)
 
 	// Check whether the 'h' parameter(hourly forecast) is specified
 	if req.URL.Query().Has("h") {
-		cachedValue, found := hCache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-		if found {
-			forecast := deepCopyForecast(cachedValue)
-			fmtHourlyForecast(&forecast, isImperial)
-			jsonValue(res, forecast)
-			return
-		}
-
-		city, err := model.GetCoordinates(cityName, vars.Token)
+		forecast, err := fetchForecast(loc, hCache, vars, model.HOURLY)
 		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		forecast, err := model.GetForecast[types.HourlyForecast](&city, vars.Token, model.HOURLY)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
+		localizeHourlyForecast(&forecast, timezone, lang)
+		if table {
+			textValue(res, renderHourlyTable(loc.name, forecast, unit, lang))
 			return
 		}
 
-		hCache.AddEntry(deepCopyForecast(forecast), fmtKey(cityName))
-		fmtHourlyForecast(&forecast, isImperial)
-		jsonValue(res, forecast)
+		fmtHourlyForecast(&forecast, unit)
+		writeValue(res, req, forecast)
 	} else { // Daily forecast(default)
-		cachedValue, found := dCache.GetEntry(fmtKey(cityName), vars.TimeToLive)
-		if found {
-			forecast := deepCopyForecast(cachedValue)
-			fmtDailyForecast(&forecast, isImperial)
-			jsonValue(res, forecast)
-			return
-		}
-
-		city, err := model.GetCoordinates(cityName, vars.Token)
+		forecast, err := fetchForecast(loc, dCache, vars, model.DAILY)
 		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		forecast, err := model.GetForecast[types.DailyForecast](&city, vars.Token, model.DAILY)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
+		localizeDailyForecast(&forecast, timezone, lang)
+		if table {
+			textValue(res, renderDailyTable(loc.name, forecast, unit, lang))
 			return
 		}
 
-		dCache.AddEntry(deepCopyForecast(forecast), fmtKey(cityName))
-		fmtDailyForecast(&forecast, isImperial)
-		jsonValue(res, forecast)
+		fmtDailyForecast(&forecast, unit)
+		writeValue(res, req, forecast)
 	}
 }
 
 func GetMoon(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Moon], vars *types.Variables) {
 	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	cachedValue, found := cache.GetEntry(fmtKey("moon"), vars.TimeToLive)
-	if found {
-		// Format moon object and then return it
-		cachedValue.Percentage = fmt.Sprintf("%s%%", cachedValue.Percentage)
-
-		jsonValue(res, cachedValue)
-	} else {
-		// Get moon data
-		moon, err := model.GetMoon(vars.Token)
-		if err != nil {
-			jsonError(res, "error", err.Error(), http.StatusBadRequest)
-			return
-		}
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		// Add result to cache
-		cache.AddEntry(moon, fmtKey("moon"))
+	moon, err := fetchMoon(cache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		// Format moon object and then return it
-		moon.Percentage = fmt.Sprintf("%s%%", moon.Percentage)
+	// Format moon object and then return it
+	moon.Phase = i18n.MoonPhase(moon.Phase, lang)
+	moon.Percentage = fmt.Sprintf("%s%%", moon.Percentage)
 
-		jsonValue(res, moon)
-	}
+	writeValue(res, req, moon)
 }
 
 func addRandomStatistics(statDB *cache.StatCache, city string, n int, meanTemp, stdDev float64) {
@@ -390,43 +450,181 @@ func addRandomStatistics(statDB *cache.StatCache, city string, n int, meanTemp,
 	}
 }
 
-func GetStatistics(res http.ResponseWriter, req *http.Request, statCache *cache.StatCache) {
+func GetStatistics(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
 	if req.Method != http.MethodGet {
-		jsonError(res, "error", "method not allowed", http.StatusMethodNotAllowed)
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/stats/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/stats/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cityKey, err := loc.key()
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Extract city name from '/stats/:city'
-	path := strings.TrimPrefix(req.URL.Path, "/stats/")
-	cityName := strings.Trim(path, "/") // Remove trailing slash if present
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if cityName == "" {
-		jsonError(res, "error", "specify city name", http.StatusMethodNotAllowed)
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Check whether the 'i' parameter(imperial mode) is specified
-	isImperial := req.URL.Query().Has("i")
+	// Check whether the 'groupBy' parameter(weekly/monthly aggregation) is specified
+	if req.URL.Query().Has("groupBy") {
+		groupedStats, err := model.GetGroupedStatistics(cityKey, req.URL.Query().Get("groupBy"), statCache)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Format grouped statistics object and then return it
+		for idx := range groupedStats.Periods {
+			period := &groupedStats.Periods[idx]
+			period.Min = fmtTemperature(period.Min, unit)
+			period.Max = fmtTemperature(period.Max, unit)
+			period.Mean = fmtTemperature(period.Mean, unit)
+			period.StdDev = fmtStdDev(period.StdDev, unit)
+			period.Median = fmtTemperature(period.Median, unit)
+			period.From.Lang = lang
+			period.To.Lang = lang
+		}
+
+		writeValue(res, req, groupedStats)
+		return
+	}
+
+	// Check whether the 'confidence' parameter(confidence level) is specified
+	confidence, err := getConfidence(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Get city statistics
-	stats, err := model.GetStatistics(fmtKey(cityName), statCache)
+	stats, err := model.GetStatistics(cityKey, confidence, statCache)
 	if err != nil {
-		jsonError(res, "error", err.Error(), http.StatusBadRequest)
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Format statistics object and then return it
-	stats.Min = fmtTemperature(stats.Min, isImperial)
-	stats.Max = fmtTemperature(stats.Max, isImperial)
-	stats.Mean = fmtTemperature(stats.Mean, isImperial)
-	stats.StdDev = fmtStdDev(stats.StdDev, isImperial)
-	stats.Median = fmtTemperature(stats.Median, isImperial)
-	stats.Mode = fmtTemperature(stats.Mode, isImperial)
-	if stats.Anomaly != nil {
-		for idx, val := range *stats.Anomaly {
-			(*stats.Anomaly)[idx].Temp = fmtTemperature(val.Temp, isImperial)
+	localizeStatistics(&stats, lang)
+	fmtStatistics(&stats, unit)
+
+	writeValue(res, req, stats)
+}
+
+func GetStatisticsComparison(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city names from '/stats/compare?cities=a,b,c'
+	cityNames := getCityNames(strings.Split(req.URL.Query().Get("cities"), ","))
+	if len(cityNames) < 2 {
+		writeError(res, req, "error", "specify at least two cities", http.StatusBadRequest)
+		return
+	}
+
+	if len(cityNames) > maxBatchSize {
+		writeError(res, req, "error", fmt.Sprintf("too many cities, the limit is %d", maxBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	// Resolve the cities, skipping those that refer to the same location(e.g. an alias and its city)
+	var names, cityKeys []string
+	seen := make(map[string]bool)
+	for _, cityName := range cityNames {
+		cityKey, err := newLocation(cityName, resolveCache, vars).key()
+		if err != nil {
+			writeError(res, req, "error", fmt.Sprintf("%s: %s", cityName, err), http.StatusBadRequest)
+			return
+		}
+
+		if seen[cityKey] {
+			continue
+		}
+
+		seen[cityKey] = true
+		names = append(names, cityName)
+		cityKeys = append(cityKeys, cityKey)
+	}
+
+	if len(cityKeys) < 2 {
+		writeError(res, req, "error", "specify at least two distinct cities", http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check whether the 'confidence' parameter(confidence level) is specified
+	confidence, err := getConfidence(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Compare cities statistics
+	comparison, err := model.GetStatisticsComparison(names, cityKeys, confidence, statCache)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Format comparison object and then return it
+	for idx := range comparison.Cities {
+		localizeStatistics(&comparison.Cities[idx].Statistics, lang)
+		fmtStatistics(&comparison.Cities[idx].Statistics, unit)
+	}
+
+	for idx := range comparison.Pairs {
+		pair := &comparison.Pairs[idx]
+		pair.From.Lang = lang
+		pair.To.Lang = lang
+		if pair.MeanDiff != nil {
+			meanDiff := fmtTemperatureDiff(*pair.MeanDiff, unit)
+			pair.MeanDiff = &meanDiff
 		}
 	}
 
-	jsonValue(res, stats)
+	writeValue(res, req, comparison)
 }
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetStatisticsComparisonCities(t *testing.T) {
+	coordinate := func(val float64) *float64 { return &val }
+
+	aliases := model.NewAliasStore()
+	aliases.Set(types.Alias{Name: "home", Lat: coordinate(45.4642), Lon: coordinate(9.19)})
+	aliases.Set(types.Alias{Name: "casa", Lat: coordinate(45.4642), Lon: coordinate(9.19)})
+
+	tooMany := make([]string, maxBatchSize+1)
+	for idx := range tooMany {
+		tooMany[idx] = fmt.Sprintf("City %d", idx)
+	}
+
+	tests := []struct {
+		Name     string
+		Cities   string
+		Expected string
+	}{
+		{"Single city", "home", "specify at least two cities"},
+		{"Same location", "home,casa", "specify at least two distinct cities"},
+		{"Too many cities", strings.Join(tooMany, ","), "too many cities"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/stats/compare?cities="+url.QueryEscape(test.Cities), nil)
+
+			GetStatisticsComparison(res, req, &cache.MasterCache[types.Locations]{}, nil, &types.Variables{Aliases: aliases})
+			if res.Code != http.StatusBadRequest || !strings.Contains(res.Body.String(), test.Expected) {
+				t.Errorf("Got %d(%s), wanted %d(%s)", res.Code, res.Body.String(), http.StatusBadRequest, test.Expected)
+			}
+		})
+	}
+}
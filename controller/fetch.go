@@ -0,0 +1,381 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Number of decimal digits of the coordinates of a location
+const coordinatesPrecision = 2
+
+// The following methods retrieve the (unformatted) resources of a location,
+// either from the cache or from OpenWeatherMap. Returned values can be
+// safely formatted without altering the cached ones.
+
+// location, representing a city whose coordinates are resolved on first
+// use. Sharing a location across the fetch methods geocodes it only once
+type location struct {
+	name string
+	city *types.City
+	err  error
+	// Whether the location has been specified through its coordinates
+	coordinates bool
+	// Cache of the resolved names
+	resolveCache *cache.MasterCache[types.Locations]
+	vars         *types.Variables
+}
+
+// Returns a location from its name. Aliases(e.g. 'home') are resolved
+// before geocoding, either to their coordinates or to their query
+func newLocation(cityName string, resolveCache *cache.MasterCache[types.Locations], vars *types.Variables) *location {
+	if vars.Aliases == nil {
+		return newCityLocation(cityName, resolveCache, vars)
+	}
+
+	if alias, found := vars.Aliases.Get(cityName); found {
+		if alias.Query == "" {
+			loc := newCoordinates(*alias.Lat, *alias.Lon)
+			loc.city.Name = alias.Name
+
+			return loc
+		}
+
+		cityName = alias.Query
+	}
+
+	return newCityLocation(cityName, resolveCache, vars)
+}
+
+// Returns a location from the name of a city, without resolving the aliases(e.g. for the
+// names recorded in the statistics database, which must not change when an alias is defined)
+func newCityLocation(cityName string, resolveCache *cache.MasterCache[types.Locations], vars *types.Variables) *location {
+	return &location{name: cityName, resolveCache: resolveCache, vars: vars}
+}
+
+// Rounds a coordinate to the precision of the cache keys
+func roundCoordinate(val float64) float64 {
+	// Adding zero turns a negative zero into a positive one
+	return math.Round(val*math.Pow10(coordinatesPrecision))/math.Pow10(coordinatesPrecision) + 0
+}
+
+// Returns the canonical key of a set of coordinates(e.g. '45.46,9.19')
+func coordinatesKey(lat, lon float64) string {
+	return fmt.Sprintf("%.*f,%.*f", coordinatesPrecision, roundCoordinate(lat), coordinatesPrecision, roundCoordinate(lon))
+}
+
+// Returns a location whose coordinates are already known. Coordinates are rounded,
+// so that nearby locations(i.e. within about a kilometer) share the same cache entries
+func newCoordinates(lat, lon float64) *location {
+	name := coordinatesKey(lat, lon)
+	city := &types.City{Name: name, Lat: roundCoordinate(lat), Lon: roundCoordinate(lon)}
+
+	return &location{name: name, city: city, coordinates: true}
+}
+
+// Returns the cache/database key of the location, that is, its rounded coordinates.
+// Different names of the same location(e.g. 'Milan' and 'Milano,IT') share the same entries
+func (loc *location) key() (string, error) {
+	city, err := loc.resolve()
+	if err != nil {
+		return "", err
+	}
+
+	return coordinatesKey(city.Lat, city.Lon), nil
+}
+
+// Returns the coordinates of the location, geocoding it on the first call
+func (loc *location) resolve() (types.City, error) {
+	if loc.city == nil && loc.err == nil {
+		loc.city, loc.err = loc.geocode()
+	}
+
+	if loc.err != nil {
+		return types.City{}, loc.err
+	}
+
+	return *loc.city, nil
+}
+
+// Geocodes the name of the location
+func (loc *location) geocode() (*types.City, error) {
+	cacheKey := fmtKey(loc.name)
+
+	cachedValue, found := loc.resolveCache.GetEntry(cacheKey, loc.vars.TimeToLive)
+	if found && len(cachedValue.Locations) > 0 {
+		city := cachedValue.Locations[0]
+		return &city, nil
+	}
+
+	city, err := model.GetCoordinates(loc.name, loc.vars.Geocoder, loc.vars.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add result to cache, resolved locations are suggested afterwards
+	loc.resolveCache.AddEntry(types.Locations{Locations: []types.City{city}}, cacheKey)
+	if loc.vars.Suggestions != nil {
+		loc.vars.Suggestions.Add(city)
+	}
+
+	return &city, nil
+}
+
+// Retrieves the coordinates from the 'lat' and 'lon' parameters
+func getCoordinates(req *http.Request) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(req.URL.Query().Get("lat"), 64)
+	if err != nil || math.IsNaN(lat) || math.IsInf(lat, 0) || lat < -90 || lat > 90 {
+		return 0, 0, errors.New("latitude must be between -90 and 90")
+	}
+
+	lon, err := strconv.ParseFloat(req.URL.Query().Get("lon"), 64)
+	if err != nil || math.IsNaN(lon) || math.IsInf(lon, 0) || lon < -180 || lon > 180 {
+		return 0, 0, errors.New("longitude must be between -180 and 180")
+	}
+
+	return lat, lon, nil
+}
+
+// Retrieves the location from the 'lat' and 'lon' parameters or,
+// when they are not specified, from the city name
+func getLocation(
+	req *http.Request,
+	cityName string,
+	resolveCache *cache.MasterCache[types.Locations],
+	vars *types.Variables,
+) (*location, error) {
+	if !req.URL.Query().Has("lat") && !req.URL.Query().Has("lon") {
+		if cityName == "" {
+			return nil, errors.New("specify city name or coordinates")
+		}
+
+		return newLocation(cityName, resolveCache, vars), nil
+	}
+
+	lat, lon, err := getCoordinates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCoordinates(lat, lon), nil
+}
+
+// Retrieves the weather of a location. Unless 'record' is false(e.g. for the places
+// surrounding a location), the daily temperature is added to the statistics database
+func fetchWeather(
+	loc *location,
+	lang i18n.Language,
+	cache *cache.MasterCache[types.Weather],
+	statCache *cache.StatCache,
+	record bool,
+	vars *types.Variables,
+) (types.Weather, error) {
+	locKey, err := loc.key()
+	if err != nil {
+		return types.Weather{}, err
+	}
+
+	// Get city coordinates
+	city, err := loc.resolve()
+	if err != nil {
+		return types.Weather{}, err
+	}
+
+	// Echo the resolved location(without the names in other languages). Cached
+	// values are shared across the names of a location, hence the location is
+	// replaced with the requested one
+	city.LocalNames = nil
+
+	// Weather alerts are translated by OpenWeatherMap,
+	// therefore the language is part of the cache key
+	cacheKey := locKey + "@" + strings.ToUpper(string(lang))
+
+	cachedValue, found := cache.GetEntry(cacheKey, vars.TimeToLive)
+	if found {
+		cachedValue.Location = &city
+		return cachedValue, nil
+	}
+
+	// Get city weather
+	weather, dailyTemp, err := model.GetWeather(&city, vars.Token, lang)
+	if err != nil {
+		return types.Weather{}, err
+	}
+
+	weather.Location = &city
+
+	// Add result to cache
+	cache.AddEntry(weather, cacheKey)
+
+	if !record {
+		return weather, nil
+	}
+
+	// Insert new statistic entry into the statistics database
+	// using the current date of the city
+	location := cityLocation(nil, weather.Timezone, weather.TimezoneOffset)
+	currentDate := time.Now().In(location).Format("2006-01-02")
+	statCache.AddStatistic(locKey, currentDate, dailyTemp)
+
+	return weather, nil
+}
+
+func fetchMetrics(
+	loc *location,
+	cache *cache.MasterCache[types.Metrics],
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) (types.Metrics, error) {
+	locKey, err := loc.key()
+	if err != nil {
+		return types.Metrics{}, err
+	}
+
+	cachedValue, found := cache.GetEntry(locKey, vars.TimeToLive)
+	if found {
+		return cachedValue, nil
+	}
+
+	// Get city coordinates
+	city, err := loc.resolve()
+	if err != nil {
+		return types.Metrics{}, err
+	}
+
+	// Get city metrics
+	metrics, err := model.GetMetrics(&city, vars.Token)
+	if err != nil {
+		return types.Metrics{}, err
+	}
+
+	// Record the pressure reading and compute the pressure tendency
+	pressure, _ := strconv.ParseFloat(metrics.Pressure, 64)
+	pressureCache.AddReading(locKey, pressure, time.Now())
+	metrics.Tendency = model.GetPressureTendency(locKey, pressureCache, vars.PressureDrop, vars.TimeToLive)
+
+	// Add result to cache
+	cache.AddEntry(metrics, locKey)
+
+	return metrics, nil
+}
+
+func fetchWind(loc *location, cache *cache.MasterCache[types.Wind], vars *types.Variables) (types.Wind, error) {
+	locKey, err := loc.key()
+	if err != nil {
+		return types.Wind{}, err
+	}
+
+	cachedValue, found := cache.GetEntry(locKey, vars.TimeToLive)
+	if found {
+		return cachedValue, nil
+	}
+
+	// Get city coordinates
+	city, err := loc.resolve()
+	if err != nil {
+		return types.Wind{}, err
+	}
+
+	// Get city wind
+	wind, err := model.GetWind(&city, vars.Token)
+	if err != nil {
+		return types.Wind{}, err
+	}
+
+	// Add result to cache
+	cache.AddEntry(wind, locKey)
+
+	return wind, nil
+}
+
+func fetchForecast[T types.DailyForecast | types.HourlyForecast](
+	loc *location,
+	cache *cache.MasterCache[T],
+	vars *types.Variables,
+	fcType model.FCType,
+) (T, error) {
+	var zero T
+
+	locKey, err := loc.key()
+	if err != nil {
+		return zero, err
+	}
+
+	cachedValue, found := cache.GetEntry(locKey, vars.TimeToLive)
+	if found {
+		return deepCopyForecast(cachedValue), nil
+	}
+
+	// Get city coordinates
+	city, err := loc.resolve()
+	if err != nil {
+		return zero, err
+	}
+
+	// Get city forecast
+	forecast, err := model.GetForecast[T](&city, vars.Token, fcType)
+	if err != nil {
+		return zero, err
+	}
+
+	// Add result to cache
+	cache.AddEntry(deepCopyForecast(forecast), locKey)
+
+	return forecast, nil
+}
+
+func fetchMoon(cache *cache.MasterCache[types.Moon], vars *types.Variables) (types.Moon, error) {
+	cachedValue, found := cache.GetEntry(fmtKey("moon"), vars.TimeToLive)
+	if found {
+		return cachedValue, nil
+	}
+
+	// Get moon data
+	moon, err := model.GetMoon(vars.Token)
+	if err != nil {
+		return types.Moon{}, err
+	}
+
+	// Add result to cache
+	cache.AddEntry(moon, fmtKey("moon"))
+
+	return moon, nil
+}
+
+func fetchPlaces(loc *location, cache *cache.MasterCache[types.Locations], vars *types.Variables) (types.Locations, error) {
+	cacheKey, err := loc.key()
+	if err != nil {
+		return types.Locations{}, err
+	}
+
+	cachedValue, found := cache.GetEntry(cacheKey, vars.TimeToLive)
+	if found {
+		return cachedValue, nil
+	}
+
+	// Get city coordinates
+	city, err := loc.resolve()
+	if err != nil {
+		return types.Locations{}, err
+	}
+
+	// Get the nearest places
+	places, err := model.GetPlaces(city.Lat, city.Lon, model.MaxLocations, vars.Token)
+	if err != nil {
+		return types.Locations{}, err
+	}
+
+	// Add result to cache
+	cache.AddEntry(types.Locations{Locations: places}, cacheKey)
+
+	return types.Locations{Locations: places}, nil
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/geonames"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetCoordinates(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Query string
+		Lat   float64
+		Lon   float64
+		Error bool
+	}{
+		{"Valid", "lat=45.4642&lon=9.19", 45.4642, 9.19, false},
+		{"Bounds", "lat=-90&lon=180", -90, 180, false},
+		{"Missing longitude", "lat=45.46", 0, 0, true},
+		{"Invalid latitude", "lat=north&lon=9.19", 0, 0, true},
+		{"Latitude out of range", "lat=90.1&lon=9.19", 0, 0, true},
+		{"Longitude out of range", "lat=45.46&lon=-180.5", 0, 0, true},
+		{"NaN latitude", "lat=NaN&lon=9.19", 0, 0, true},
+		{"NaN longitude", "lat=45.46&lon=nan", 0, 0, true},
+		{"Infinite latitude", "lat=Inf&lon=9.19", 0, 0, true},
+		{"Infinite longitude", "lat=45.46&lon=-Inf", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather?"+test.Query, nil)
+
+			lat, lon, err := getCoordinates(req)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got (%v, %v), wanted an error", lat, lon)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if lat != test.Lat || lon != test.Lon {
+				t.Errorf("Got (%v, %v), wanted (%v, %v)", lat, lon, test.Lat, test.Lon)
+			}
+		})
+	}
+}
+
+func TestCoordinatesKey(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Lat      float64
+		Lon      float64
+		Expected string
+	}{
+		{"Rounding", 45.4642, 9.1900, "45.46,9.19"},
+		{"Rounding up", 45.4651, 9.1949, "45.47,9.19"},
+		{"Padding", 45, -9.1, "45.00,-9.10"},
+		{"Negative", -33.8688, -151.2093, "-33.87,-151.21"},
+		{"Negative zero", math.Copysign(0, -1), -0.001, "0.00,0.00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := coordinatesKey(test.Lat, test.Lon)
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestNewCoordinates(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Lat         float64
+		Lon         float64
+		Key         string
+		ExpectedLat float64
+		ExpectedLon float64
+	}{
+		{"Rounded", 45.4642, 9.1900, "45.46,9.19", 45.46, 9.19},
+		{"Nearby location", 45.4638, 9.1851, "45.46,9.19", 45.46, 9.19},
+		{"Negative", -33.8688, -151.2093, "-33.87,-151.21", -33.87, -151.21},
+		{"Negative zero", -0.004, math.Copysign(0, -1), "0.00,0.00", 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			loc := newCoordinates(test.Lat, test.Lon)
+
+			key, err := loc.key()
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if key != test.Key || loc.name != test.Key || !loc.coordinates {
+				t.Errorf("Got %s, wanted %s", key, test.Key)
+			}
+
+			// Negative zero must not leak into the coordinates
+			lat, lon := loc.city.Lat, loc.city.Lon
+			if lat != test.ExpectedLat || lon != test.ExpectedLon ||
+				math.Signbit(lat) != math.Signbit(test.ExpectedLat) || math.Signbit(lon) != math.Signbit(test.ExpectedLon) {
+				t.Errorf("Got (%v, %v), wanted (%v, %v)", lat, lon, test.ExpectedLat, test.ExpectedLon)
+			}
+		})
+	}
+}
+
+func TestNewLocation(t *testing.T) {
+	coordinate := func(val float64) *float64 { return &val }
+
+	aliases := model.NewAliasStore()
+	aliases.Set(types.Alias{Name: "home", Lat: coordinate(45.4642), Lon: coordinate(9.19)})
+	aliases.Set(types.Alias{Name: "office", Query: "Rome,IT"})
+
+	tests := []struct {
+		Name        string
+		CityName    string
+		Aliases     types.AliasStore
+		Expected    string
+		Coordinates bool
+	}{
+		{"City", "Milan", aliases, "Milan", false},
+		{"Coordinates alias", "HOME", aliases, "home", true},
+		{"Query alias", "office", aliases, "Rome,IT", false},
+		{"Without aliases", "home", nil, "home", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			loc := newLocation(test.CityName, &cache.MasterCache[types.Locations]{}, &types.Variables{Aliases: test.Aliases})
+
+			name := loc.name
+			if loc.coordinates {
+				name = loc.city.Name
+			}
+
+			if name != test.Expected || loc.coordinates != test.Coordinates {
+				t.Errorf("Got %s(%t), wanted %s(%t)", name, loc.coordinates, test.Expected, test.Coordinates)
+			}
+		})
+	}
+
+	// Legacy names of the statistics database are not aliased
+	if loc := newCityLocation("home", &cache.MasterCache[types.Locations]{}, &types.Variables{Aliases: aliases}); loc.coordinates || loc.name != "home" {
+		t.Errorf("Got %s, wanted home", loc.name)
+	}
+}
+
+func TestResolveWithoutServices(t *testing.T) {
+	// The offline geocoder resolves the location, while suggestions and aliases are unset
+	index := &geonames.Index{}
+	index.Add(types.City{Name: "Milan", Country: "IT", Lat: 45.4642, Lon: 9.19})
+	vars := &types.Variables{TimeToLive: 1, Geocoder: index}
+
+	key, err := newLocation("Milan", &cache.InitMasterCache().ResolveCache, vars).key()
+	if err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	if key != "45.46,9.19" {
+		t.Errorf("Got %s, wanted 45.46,9.19", key)
+	}
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func getLimit(req *http.Request) (int, error) {
+	// Maximum number of candidates, 5 by default
+	if !req.URL.Query().Has("limit") {
+		return model.MaxLocations, nil
+	}
+
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit < 1 || limit > model.MaxLocations {
+		return 0, fmt.Errorf("limit must be between 1 and %d", model.MaxLocations)
+	}
+
+	return limit, nil
+}
+
+// Retrieves the nearest place of a location specified through its coordinates
+// when the 'place' parameter is specified(either without a value or as a boolean). Returns nil otherwise
+func getPlace(
+	req *http.Request,
+	loc *location,
+	cache *cache.MasterCache[types.Locations],
+	vars *types.Variables,
+) (*types.City, error) {
+	if !req.URL.Query().Has("place") {
+		return nil, nil
+	}
+
+	enabled := true
+	if val := req.URL.Query().Get("place"); val != "" {
+		parsedVal, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for 'place', use either 'true' or 'false'", val)
+		}
+		enabled = parsedVal
+	}
+
+	if !enabled || !loc.coordinates {
+		return nil, nil
+	}
+
+	places, err := fetchPlaces(loc, cache, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(places.Locations) == 0 {
+		return nil, nil
+	}
+
+	// Keep the requested coordinates rather than the ones of the place
+	place := places.Locations[0]
+	place.Lat, place.Lon = loc.city.Lat, loc.city.Lon
+	place.LocalNames = nil
+
+	return &place, nil
+}
+
+func GetLocations(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Locations], vars *types.Variables) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract the query from '/geo/search?q=city,state,country'(or 'q=zip,country')
+	query := strings.TrimSpace(req.URL.Query().Get("q"))
+	if query == "" {
+		writeError(res, req, "error", "specify a query", http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the maximum number of candidates from the 'limit' parameter
+	limit, err := getLimit(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Candidates are cached regardless of the limit
+	locations, found := cache.GetEntry(fmtKey(query), vars.TimeToLive)
+	if !found {
+		candidates, err := model.SearchLocations(query, model.MaxLocations, vars.Geocoder, vars.Token)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		locations = types.Locations{Locations: candidates}
+		cache.AddEntry(locations, fmtKey(query))
+	}
+
+	locations.Locations = locations.Locations[:min(len(locations.Locations), limit)]
+
+	writeValue(res, req, locations)
+}
+
+func GetSuggestions(res http.ResponseWriter, req *http.Request, vars *types.Variables) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract the partial query from '/geo/autocomplete?q=mil'
+	query := strings.TrimSpace(req.URL.Query().Get("q"))
+	if query == "" {
+		writeError(res, req, "error", "specify a query", http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the maximum number of suggestions from the 'limit' parameter
+	limit, err := getLimit(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Without an index, there is nothing to suggest
+	suggestions := []types.City{}
+	if vars.Suggestions != nil {
+		suggestions = vars.Suggestions.Autocomplete(query, limit)
+	}
+
+	writeValue(res, req, types.Locations{Locations: suggestions})
+}
+
+func GetPlaces(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Locations], vars *types.Variables) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract the coordinates from '/geo/reverse?lat=&lon='
+	lat, lon, err := getCoordinates(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the maximum number of places from the 'limit' parameter
+	limit, err := getLimit(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	places, err := fetchPlaces(newCoordinates(lat, lon), cache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	places.Locations = places.Locations[:min(len(places.Locations), limit)]
+
+	writeValue(res, req, places)
+}
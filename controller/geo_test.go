@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/geonames"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestGetPlace(t *testing.T) {
+	// The nearest places are served by the cache, hence no request is sent upstream
+	reverseCache := &cache.InitMasterCache().ReverseGeoCache
+	reverseCache.AddEntry(types.Locations{Locations: []types.City{
+		{Name: "Milan", Country: "IT", Lat: 45.4643, Lon: 9.1895, LocalNames: map[string]string{"it": "Milano"}},
+	}}, "45.46,9.19")
+	vars := &types.Variables{TimeToLive: 1}
+
+	tests := []struct {
+		Name     string
+		Location *location
+		Query    string
+		Expected string
+		Error    bool
+	}{
+		{"Not requested", newCoordinates(45.46, 9.19), "", "", false},
+		{"Requested", newCoordinates(45.46, 9.19), "place", "Milan", false},
+		{"Boolean", newCoordinates(45.46, 9.19), "place=true", "Milan", false},
+		{"Disabled", newCoordinates(45.46, 9.19), "place=false", "", false},
+		{"Named location", &location{name: "Milan"}, "place", "", false},
+		{"Invalid value", newCoordinates(45.46, 9.19), "place=maybe", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/?"+test.Query, nil)
+
+			got, err := getPlace(req, test.Location, reverseCache, vars)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got %v, wanted an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			var name string
+			if got != nil {
+				name = got.Name
+
+				// The place keeps the requested coordinates
+				if got.Lat != 45.46 || got.Lon != 9.19 || got.LocalNames != nil {
+					t.Errorf("Got %+v, wanted the requested coordinates", *got)
+				}
+			}
+
+			if name != test.Expected {
+				t.Errorf("Got '%s', wanted '%s'", name, test.Expected)
+			}
+		})
+	}
+}
+
+func TestGetSuggestions(t *testing.T) {
+	index := &geonames.Index{}
+	index.Add(types.City{Name: "Milan", Country: "IT", Lat: 45.46, Lon: 9.19})
+
+	tests := []struct {
+		Name        string
+		Suggestions types.Suggestions
+		Expected    string
+	}{
+		{"Index", index, `{"locations":[{"name":"Milan","country":"IT","lat":45.46,"lon":9.19}]}`},
+		{"Without index", nil, `{"locations":[]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/geo/autocomplete?q=mil", nil)
+
+			GetSuggestions(res, req, &types.Variables{Suggestions: test.Suggestions})
+			if got := strings.TrimSpace(res.Body.String()); got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func getTimezone(req *http.Request) (*time.Location, error) {
+	// Dates are rendered in the city timezone unless
+	// the 'tz' parameter(either 'utc' or an IANA timezone) is specified
+	tz := req.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+
+	if strings.EqualFold(tz, "utc") {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s'", tz)
+	}
+
+	return location, nil
+}
+
+func getLanguage(req *http.Request) (i18n.Language, error) {
+	// The 'lang' parameter takes precedence over the Accept-Language header
+	return i18n.Negotiate(req.URL.Query().Get("lang"), req.Header.Get("Accept-Language"))
+}
+
+func cityLocation(requested *time.Location, timezone string, offset int) *time.Location {
+	if requested != nil {
+		return requested
+	}
+
+	if timezone == "" {
+		return time.UTC
+	}
+
+	// Fallback to the UTC offset of the city if its timezone is unknown
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.FixedZone(timezone, offset)
+	}
+
+	return location
+}
+
+func localizeWeather(weather *types.Weather, requested *time.Location, lang i18n.Language) {
+	location := cityLocation(requested, weather.Timezone, weather.TimezoneOffset)
+
+	weather.Timezone = location.String()
+	weather.Date.Date = weather.Date.Date.In(location)
+	weather.Date.Lang = lang
+	weather.Condition = i18n.Condition(weather.Condition, lang)
+
+	// Copy the alerts to avoid mutating the cached value
+	weather.Alerts = slices.Clone(weather.Alerts)
+	for idx := range weather.Alerts {
+		alert := &weather.Alerts[idx]
+		alert.Start.Date = alert.Start.Date.In(location)
+		alert.End.Date = alert.End.Date.In(location)
+		alert.Start.Lang = lang
+		alert.End.Lang = lang
+	}
+}
+
+func localizeDailyForecast(forecast *types.DailyForecast, requested *time.Location, lang i18n.Language) {
+	location := cityLocation(requested, forecast.Timezone, forecast.TimezoneOffset)
+
+	forecast.Timezone = location.String()
+	for idx := range forecast.Forecast {
+		val := &forecast.Forecast[idx]
+		val.Date.Date = val.Date.Date.In(location)
+		val.Date.Lang = lang
+		val.Condition = i18n.Condition(val.Condition, lang)
+	}
+}
+
+func localizeHourlyForecast(forecast *types.HourlyForecast, requested *time.Location, lang i18n.Language) {
+	location := cityLocation(requested, forecast.Timezone, forecast.TimezoneOffset)
+
+	forecast.Timezone = location.String()
+	for idx := range forecast.Forecast {
+		val := &forecast.Forecast[idx]
+		val.Time.Time = val.Time.Time.In(location)
+		val.Time.Lang = lang
+		val.Condition = i18n.Condition(val.Condition, lang)
+	}
+}
+
+func localizeStatistics(stats *types.StatResult, lang i18n.Language) {
+	if stats.Anomaly != nil {
+		for idx := range *stats.Anomaly {
+			(*stats.Anomaly)[idx].Date.Lang = lang
+		}
+	}
+}
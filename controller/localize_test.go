@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetTimezone(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Query    string
+		Expected string
+		Error    bool
+	}{
+		{"City timezone", "", "", false},
+		{"IANA timezone", "tz=America/New_York", "America/New_York", false},
+		{"UTC", "tz=UTC", "UTC", false},
+		{"Lowercase UTC", "tz=utc", "UTC", false},
+		{"Invalid timezone", "tz=Mars/Olympus", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/weather/milan?"+test.Query, nil)
+
+			got, err := getTimezone(req)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got %v, wanted an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			var name string
+			if got != nil {
+				name = got.String()
+			}
+
+			if name != test.Expected {
+				t.Errorf("Got %s, wanted %s", name, test.Expected)
+			}
+		})
+	}
+}
+
+func TestCityLocation(t *testing.T) {
+	date := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name      string
+		Requested *time.Location
+		Timezone  string
+		Offset    int
+		Expected  string
+	}{
+		{"Requested timezone", time.UTC, "Europe/Rome", 7200, "2025-06-01T12:00:00Z"},
+		{"City timezone", nil, "Europe/Rome", 7200, "2025-06-01T14:00:00+02:00"},
+		{"Unknown timezone", nil, "Mars/Olympus", -10800, "2025-06-01T09:00:00-03:00"},
+		{"Missing timezone", nil, "", 3600, "2025-06-01T12:00:00Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := date.In(cityLocation(test.Requested, test.Timezone, test.Offset)).Format(time.RFC3339)
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
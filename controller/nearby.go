@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/geonames"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+const (
+	// Default and maximum search radius, expressed in kilometers
+	defaultNearbyRadius = 50
+	maxNearbyRadius     = 500
+	// Default and maximum number of nearby places
+	defaultNearbyLimit = 10
+	maxNearbyLimit     = 20
+)
+
+// Retrieves the search radius(in kilometers) and the maximum number
+// of places from the 'radius' and the 'limit' parameters
+func getNearbyParams(req *http.Request) (float64, int, error) {
+	radius := float64(defaultNearbyRadius)
+	if req.URL.Query().Has("radius") {
+		parsedRadius, err := units.ParseDistance(req.URL.Query().Get("radius"))
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if parsedRadius <= 0 || parsedRadius > maxNearbyRadius {
+			return 0, 0, fmt.Errorf("radius must be between 0 and %dkm", maxNearbyRadius)
+		}
+		radius = parsedRadius
+	}
+
+	limit := defaultNearbyLimit
+	if req.URL.Query().Has("limit") {
+		parsedLimit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+		if err != nil || parsedLimit < 1 || parsedLimit > maxNearbyLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxNearbyLimit)
+		}
+		limit = parsedLimit
+	}
+
+	return radius, limit, nil
+}
+
+func GetNearby(
+	res http.ResponseWriter,
+	req *http.Request,
+	index *geonames.Index,
+	masterCache *cache.MasterCaches,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Neighbours are looked up in the GeoNames dump
+	if index == nil {
+		writeError(res, req, "error", "nearby places require a GeoNames dump", http.StatusNotImplemented)
+		return
+	}
+
+	// Extract city name from '/nearby/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/nearby/"), &masterCache.ResolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	radius, limit, err := getNearbyParams(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the timezone from the 'tz' parameter
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	city, err := loc.resolve()
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+	city.LocalNames = nil
+
+	neighbours := index.Nearby(city.Lat, city.Lon, radius, limit)
+	nearby := types.Nearby{Location: city, Places: make([]types.NearbyPlace, len(neighbours))}
+
+	// Retrieve the weather of the places concurrently, like a batch
+	semaphore := make(chan struct{}, batchConcurrency)
+
+	var wg sync.WaitGroup
+	for idx, neighbour := range neighbours {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			direction, _ := model.GetCardinalDir(neighbour.Bearing)
+
+			place := &nearby.Places[idx]
+			place.City = neighbour.City
+			place.Distance = units.FormatDistance(neighbour.Distance, unit.Distance)
+			place.Bearing = int(math.Round(neighbour.Bearing)) % 360
+			place.Direction = direction
+
+			// Places are keyed by their coordinates, thus sharing the weather cache. Their
+			// weather is not recorded, otherwise every lookup would seed the statistics of its neighbours
+			placeLoc := newCoordinates(neighbour.City.Lat, neighbour.City.Lon)
+			placeLoc.city.Name = neighbour.City.Name
+			placeLoc.city.Country = neighbour.City.Country
+
+			weather, err := fetchWeather(placeLoc, lang, &masterCache.WeatherCache, nil, false, vars)
+			if err != nil {
+				place.Error = err.Error()
+				return
+			}
+
+			localizeWeather(&weather, timezone, lang)
+			fmtWeather(&weather, unit)
+			place.Weather = &weather
+		})
+	}
+	wg.Wait()
+
+	writeValue(res, req, nearby)
+}
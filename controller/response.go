@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/ceticamarco/zephyr/codec"
+)
+
+// The following methods write the responses of the controllers, encoding them
+// in the format requested through the 'format' parameter or the Accept header.
+// The 'fields' parameter selects the members to keep(e.g. 'forecast.max')
+
+func isEncoding(format string) bool {
+	_, err := codec.Parse(format)
+
+	return err == nil
+}
+
+func getFormat(req *http.Request) (codec.Format, error) {
+	return codec.Negotiate(req.URL.Query().Get("format"), req.Header.Get("Accept"))
+}
+
+func encodeError(res http.ResponseWriter, format codec.Format, key string, value string, status int) {
+	res.Header().Set("Content-Type", format.ContentType())
+	res.WriteHeader(status)
+	codec.Encode(res, map[string]string{key: value}, format)
+}
+
+func getFields(req *http.Request) (codec.Fields, error) {
+	if !req.URL.Query().Has("fields") {
+		return nil, nil
+	}
+
+	return codec.ParseFields(req.URL.Query().Get("fields"))
+}
+
+func encodeValue(res http.ResponseWriter, format codec.Format, val any, fields codec.Fields) {
+	res.Header().Set("Content-Type", format.ContentType())
+	res.WriteHeader(http.StatusOK)
+	codec.EncodeFields(res, val, format, fields)
+}
+
+func writeError(res http.ResponseWriter, req *http.Request, key string, value string, status int) {
+	// Fallback to JSON when the format cannot be negotiated
+	format, err := getFormat(req)
+	if err != nil {
+		format = codec.JSON
+	}
+
+	encodeError(res, format, key, value, status)
+}
+
+func writeValue(res http.ResponseWriter, req *http.Request, val any) {
+	format, err := getFormat(req)
+	if err != nil {
+		encodeError(res, codec.JSON, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fields, err := getFields(req)
+	if err != nil {
+		encodeError(res, format, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encodeValue(res, format, val, fields)
+}
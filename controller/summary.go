@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+// Sections of a summary, included by default
+var summarySections = []string{"weather", "metrics", "wind", "daily", "hourly"}
+
+// Retrieves the sections of a summary from the 'include' and 'exclude' parameters(e.g. 'include=weather,wind')
+func getSummarySections(req *http.Request) (map[string]bool, error) {
+	parse := func(param string) ([]string, error) {
+		var sections []string
+		for _, section := range strings.Split(req.URL.Query().Get(param), ",") {
+			section = strings.ToLower(strings.TrimSpace(section))
+			if section == "" {
+				continue
+			}
+
+			if !slices.Contains(summarySections, section) {
+				return nil, fmt.Errorf("invalid section '%s', use one of '%s'", section, strings.Join(summarySections, "', '"))
+			}
+
+			sections = append(sections, section)
+		}
+
+		return sections, nil
+	}
+
+	included, err := parse("include")
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := parse("exclude")
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.URL.Query().Has("include") {
+		included = summarySections
+	}
+
+	sections := make(map[string]bool)
+	for _, section := range included {
+		sections[section] = !slices.Contains(excluded, section)
+	}
+
+	return sections, nil
+}
+
+func GetSummary(
+	res http.ResponseWriter,
+	req *http.Request,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/summary/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/summary/"), &masterCache.ResolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the unit system from the 'units' parameter(or the legacy 'i' parameter)
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the timezone from the 'tz' parameter
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the language from the 'lang' parameter(or the Accept-Language header)
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the sections from the 'include' and 'exclude' parameters
+	sections, err := getSummarySections(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Each section is retrieved from its own cache, while
+	// the city is geocoded at most once
+	summary := types.Summary{City: loc.name}
+
+	if sections["weather"] {
+		weather, err := fetchWeather(loc, lang, &masterCache.WeatherCache, statCache, true, vars)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Name the coordinates after the nearest place when the 'place' parameter is specified
+		place, err := getPlace(req, loc, &masterCache.ReverseGeoCache, vars)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if place != nil {
+			weather.Location = place
+		}
+
+		localizeWeather(&weather, timezone, lang)
+		fmtWeather(&weather, unit)
+		summary.Weather = &weather
+	}
+
+	if sections["metrics"] {
+		metrics, err := fetchMetrics(loc, &masterCache.MetricsCache, pressureCache, vars)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fmtMetrics(&metrics, unit)
+		summary.Metrics = &metrics
+	}
+
+	if sections["wind"] {
+		wind, err := fetchWind(loc, &masterCache.WindCache, vars)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		wind.Speed = fmtWind(wind.Speed, unit)
+		summary.Wind = &wind
+	}
+
+	if sections["daily"] {
+		forecast, err := fetchForecast(loc, &masterCache.DailyForecastCache, vars, model.DAILY)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		localizeDailyForecast(&forecast, timezone, lang)
+		fmtDailyForecast(&forecast, unit)
+		summary.Daily = &forecast
+	}
+
+	if sections["hourly"] {
+		forecast, err := fetchForecast(loc, &masterCache.HourlyForecastCache, vars, model.HOURLY)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		localizeHourlyForecast(&forecast, timezone, lang)
+		fmtHourlyForecast(&forecast, unit)
+		summary.Hourly = &forecast
+	}
+
+	writeValue(res, req, summary)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"maps"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSummarySections(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Query    string
+		Expected map[string]bool
+		Error    bool
+	}{
+		{"Default", "", map[string]bool{"weather": true, "metrics": true, "wind": true, "daily": true, "hourly": true}, false},
+		{"Include", "include=weather,wind", map[string]bool{"weather": true, "wind": true}, false},
+		{"Exclude", "exclude=daily,hourly", map[string]bool{"weather": true, "metrics": true, "wind": true, "daily": false, "hourly": false}, false},
+		{"Include and exclude", "include=weather,wind&exclude=wind", map[string]bool{"weather": true, "wind": false}, false},
+		{"Case and blanks", "include=%20Weather%20,,WIND", map[string]bool{"weather": true, "wind": true}, false},
+		{"Empty include", "include=", map[string]bool{}, false},
+		{"Invalid include", "include=weather,moon", nil, true},
+		{"Invalid exclude", "exclude=alerts", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/summary/milan?"+test.Query, nil)
+
+			got, err := getSummarySections(req)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got %v, wanted an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if !maps.Equal(got, test.Expected) {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
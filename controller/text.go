@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/codec"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+// ANSI escape sequences
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiBlue   = "\033[34m"
+	ansiCyan   = "\033[36m"
+)
+
+func textValue(res http.ResponseWriter, val string) {
+	res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprintln(res, val)
+}
+
+// Renders a format string by replacing each placeholder('%' followed by a character)
+// with the value returned by the corresponding function. Placeholders are evaluated lazily,
+// that is, each function is invoked only if the format string contains its placeholder
+func renderFormat(format string, placeholders map[rune]func() (string, error)) (string, error) {
+	var sb strings.Builder
+	runes := []rune(format)
+
+	for idx := 0; idx < len(runes); idx++ {
+		if runes[idx] != '%' {
+			sb.WriteRune(runes[idx])
+			continue
+		}
+
+		if idx+1 == len(runes) {
+			return "", fmt.Errorf("incomplete placeholder at the end of the format string")
+		}
+
+		idx++
+		if runes[idx] == '%' {
+			sb.WriteRune('%')
+			continue
+		}
+
+		placeholder, found := placeholders[runes[idx]]
+		if !found {
+			return "", fmt.Errorf("unknown placeholder '%%%c'", runes[idx])
+		}
+
+		value, err := placeholder()
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(value)
+	}
+
+	return sb.String(), nil
+}
+
+func weatherPlaceholders(
+	loc *location,
+	weather types.Weather,
+	unit units.System,
+	lang i18n.Language,
+	masterCache *cache.MasterCaches,
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) map[rune]func() (string, error) {
+	text := func(value string) func() (string, error) {
+		return func() (string, error) { return value, nil }
+	}
+
+	metric := func(field func(types.Metrics) string) func() (string, error) {
+		return func() (string, error) {
+			metrics, err := fetchMetrics(loc, &masterCache.MetricsCache, pressureCache, vars)
+			if err != nil {
+				return "", err
+			}
+
+			fmtMetrics(&metrics, unit)
+
+			return field(metrics), nil
+		}
+	}
+
+	moon := func(field func(types.Moon) string) func() (string, error) {
+		return func() (string, error) {
+			moon, err := fetchMoon(&masterCache.MoonCache, vars)
+			if err != nil {
+				return "", err
+			}
+
+			return field(moon), nil
+		}
+	}
+
+	return map[rune]func() (string, error){
+		'l': text(loc.name),
+		'c': text(weather.Condition),
+		'e': text(weather.Emoji),
+		't': text(fmtTemperature(weather.Temperature, unit)),
+		'f': text(fmtTemperature(weather.FeelsLike, unit)),
+		'n': text(fmtTemperature(weather.Min, unit)),
+		'x': text(fmtTemperature(weather.Max, unit)),
+		'w': func() (string, error) {
+			wind, err := fetchWind(loc, &masterCache.WindCache, vars)
+			if err != nil {
+				return "", err
+			}
+
+			return wind.Arrow + " " + fmtWind(wind.Speed, unit), nil
+		},
+		'h': metric(func(metrics types.Metrics) string { return metrics.Humidity }),
+		'p': metric(func(metrics types.Metrics) string { return metrics.Pressure }),
+		'd': metric(func(metrics types.Metrics) string { return metrics.DewPoint }),
+		'u': metric(func(metrics types.Metrics) string { return metrics.UvIndex }),
+		'm': moon(func(moon types.Moon) string { return moon.Icon }),
+		'M': moon(func(moon types.Moon) string { return i18n.MoonPhase(moon.Phase, lang) }),
+	}
+}
+
+// Checks whether the forecast should be rendered as a table, that is, when 'format=table'
+// or when the client is curl(unless another format is explicitly requested)
+func wantsTable(req *http.Request) bool {
+	switch req.URL.Query().Get("format") {
+	case "table":
+		return true
+	case "":
+		isCurl := strings.HasPrefix(req.UserAgent(), "curl/")
+		_, explicit := codec.FromAccept(req.Header.Get("Accept"))
+
+		return isCurl && !explicit
+	}
+
+	return false
+}
+
+// tableCell, representing a (optionally colored) cell of a table
+type tableCell struct {
+	text  string
+	color string
+}
+
+func temperatureColor(temp string) string {
+	// Temperature is expressed in Celsius
+	parsedTemp, _ := strconv.ParseFloat(temp, 64)
+
+	switch {
+	case parsedTemp <= 0:
+		return ansiBlue
+	case parsedTemp < 10:
+		return ansiCyan
+	case parsedTemp < 20:
+		return ansiGreen
+	case parsedTemp < 30:
+		return ansiYellow
+	}
+
+	return ansiRed
+}
+
+func rainColor(rainProb string) string {
+	if parseValue(rainProb) >= 50 {
+		return ansiBlue
+	}
+
+	return ""
+}
+
+func renderTable(title string, header []string, rows [][]tableCell) string {
+	// Compute the width of each column
+	widths := make([]int, len(header))
+	for idx, val := range header {
+		widths[idx] = utf8.RuneCountInString(val)
+	}
+
+	for _, row := range rows {
+		for idx, cell := range row {
+			widths[idx] = max(widths[idx], utf8.RuneCountInString(cell.text))
+		}
+	}
+
+	pad := func(text string, width int) string {
+		return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(ansiBold + title + ansiReset + "\n")
+
+	var line []string
+	for idx, val := range header {
+		line = append(line, ansiBold+pad(val, widths[idx])+ansiReset)
+	}
+	sb.WriteString(strings.Join(line, "  ") + "\n")
+
+	for _, row := range rows {
+		line = line[:0]
+		for idx, cell := range row {
+			// Pad before coloring, escape sequences have no width
+			text := pad(cell.text, widths[idx])
+			if cell.color != "" {
+				text = cell.color + text + ansiReset
+			}
+
+			line = append(line, text)
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(line, "  "), " ") + "\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func renderDailyTable(cityName string, forecast types.DailyForecast, unit units.System, lang i18n.Language) string {
+	rows := make([][]tableCell, 0, len(forecast.Forecast))
+	for _, val := range forecast.Forecast {
+		rows = append(rows, []tableCell{
+			{i18n.Format(val.Date.Date, "Monday, 2006/01/02", lang), ""},
+			{val.Condition, ""},
+			{fmtTemperature(val.Min, unit), temperatureColor(val.Min)},
+			{fmtTemperature(val.Max, unit), temperatureColor(val.Max)},
+			{fmtTemperature(val.FeelsLike, unit), temperatureColor(val.FeelsLike)},
+			{fmtWind(val.Wind.Speed, unit) + " " + val.Wind.Direction, ""},
+			{val.RainProb, rainColor(val.RainProb)},
+			{val.Emoji, ""},
+		})
+	}
+
+	header := []string{"Date", "Condition", "Min", "Max", "Feels like", "Wind", "Rain", ""}
+
+	return renderTable(fmt.Sprintf("%s (%s)", cityName, forecast.Timezone), header, rows)
+}
+
+func renderHourlyTable(cityName string, forecast types.HourlyForecast, unit units.System, lang i18n.Language) string {
+	rows := make([][]tableCell, 0, len(forecast.Forecast))
+	for _, val := range forecast.Forecast {
+		rows = append(rows, []tableCell{
+			{i18n.Format(val.Time.Time, i18n.TimeLayout(lang), lang), ""},
+			{val.Condition, ""},
+			{fmtTemperature(val.Temperature, unit), temperatureColor(val.Temperature)},
+			{fmtWind(val.Wind.Speed, unit) + " " + val.Wind.Direction, ""},
+			{val.RainProb, rainColor(val.RainProb)},
+			{val.Emoji, ""},
+		})
+	}
+
+	header := []string{"Time", "Condition", "Temperature", "Wind", "Rain", ""}
+
+	return renderTable(fmt.Sprintf("%s (%s)", cityName, forecast.Timezone), header, rows)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+func TestRenderFormat(t *testing.T) {
+	// Resources are served by the caches, hence no request is sent upstream
+	loc := newCoordinates(45.46, 9.19)
+	masterCache := cache.InitMasterCache()
+	masterCache.WindCache.AddEntry(types.Wind{Arrow: "↗", Direction: "SW", Speed: "12"}, "45.46,9.19")
+	masterCache.MetricsCache.AddEntry(types.Metrics{Humidity: "60", Pressure: "1013", DewPoint: "12", UvIndex: "5", Visibility: "10"}, "45.46,9.19")
+	masterCache.MoonCache.AddEntry(types.Moon{Icon: "🌕", Phase: "Full Moon", Percentage: "100"}, fmtKey("moon"))
+
+	weather := types.Weather{Temperature: "21.4", Condition: "Clear", Emoji: "☀️"}
+	vars := &types.Variables{TimeToLive: 1}
+	placeholders := weatherPlaceholders(loc, weather, units.Metric, i18n.English, masterCache, cache.InitPressureCache(), vars)
+
+	tests := []struct {
+		Name     string
+		Format   string
+		Expected string
+		Error    bool
+	}{
+		{"Temperature", "%t", "21°C", false},
+		{"Emoji", "%e", "☀️", false},
+		{"Wind", "%w", "↗ 43.2 km/h", false}, // Speed is cached in m/s
+		{"Humidity", "%h", "60%", false},
+		{"Moon", "%m", "🌕", false},
+		{"Text and placeholders", "%e %t, humidity %h", "☀️ 21°C, humidity 60%", false},
+		{"Escaped percent", "100%%", "100%", false},
+		{"Unknown placeholder", "%t %z", "", true},
+		{"Incomplete placeholder", "%t %", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := renderFormat(test.Format, placeholders)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got %s, wanted an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestWantsTable(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Query     string
+		UserAgent string
+		Accept    string
+		Expected  bool
+	}{
+		{"Curl", "", "curl/8.5.0", "*/*", true},
+		{"Browser", "", "Mozilla/5.0", "", false},
+		{"Explicit table", "format=table", "Mozilla/5.0", "", true},
+		{"Curl with explicit format", "format=json", "curl/8.5.0", "", false},
+		{"Curl with explicit Accept", "", "curl/8.5.0", "application/json", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/forecast/milan?"+test.Query, nil)
+			req.Header.Set("User-Agent", test.UserAgent)
+			if test.Accept != "" {
+				req.Header.Set("Accept", test.Accept)
+			}
+
+			if got := wantsTable(req); got != test.Expected {
+				t.Errorf("Got %t, wanted %t", got, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package controller
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+func newMeasure(value float64, unit string) types.Measure {
+	// Round to the second decimal digit to avoid floating point noise
+	return types.Measure{
+		Value: math.Round(value*100) / 100,
+		Unit:  unit,
+	}
+}
+
+func parseValue(value string) float64 {
+	parsedValue, _ := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+
+	return parsedValue
+}
+
+func temperatureMeasure(temp string, unit units.System) types.Measure {
+	return newMeasure(units.ConvertTemperature(parseValue(temp), unit.Temperature), string(unit.Temperature))
+}
+
+func temperatureDeltaMeasure(delta string, unit units.System) types.Measure {
+	return newMeasure(units.ConvertTemperatureDelta(parseValue(delta), unit.Temperature), string(unit.Temperature))
+}
+
+func windV2(wind types.Wind, unit units.System) types.WindV2 {
+	return types.WindV2{
+		Arrow:     wind.Arrow,
+		Direction: wind.Direction,
+		Speed:     newMeasure(units.ConvertSpeed(parseValue(wind.Speed), unit.Speed), string(unit.Speed)),
+	}
+}
+
+func confidenceIntervalV2(ci *types.ConfidenceInterval, unit units.System) *types.ConfidenceIntervalV2 {
+	if ci == nil {
+		return nil
+	}
+
+	return &types.ConfidenceIntervalV2{
+		Level: newMeasure(parseValue(ci.Level), "%"),
+		Lower: temperatureMeasure(ci.Lower, unit),
+		Upper: temperatureMeasure(ci.Upper, unit),
+	}
+}
+
+func GetWeatherV2(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Weather],
+	resolveCache *cache.MasterCache[types.Locations],
+	reverseCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/v2/weather/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/v2/weather/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	weather, err := fetchWeather(loc, lang, cache, statCache, true, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	place, err := getPlace(req, loc, reverseCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if place != nil {
+		weather.Location = place
+	}
+
+	localizeWeather(&weather, timezone, lang)
+
+	alerts := make([]types.WeatherAlertV2, 0, len(weather.Alerts))
+	for _, alert := range weather.Alerts {
+		alerts = append(alerts, types.WeatherAlertV2{
+			Event:       alert.Event,
+			Start:       alert.Start.Date,
+			End:         alert.End.Date,
+			Description: alert.Description,
+		})
+	}
+
+	writeValue(res, req, types.WeatherV2{
+		Timestamp:   weather.Date.Date,
+		Timezone:    weather.Timezone,
+		Temperature: temperatureMeasure(weather.Temperature, unit),
+		Min:         temperatureMeasure(weather.Min, unit),
+		Max:         temperatureMeasure(weather.Max, unit),
+		Condition:   weather.Condition,
+		FeelsLike:   temperatureMeasure(weather.FeelsLike, unit),
+		Emoji:       weather.Emoji,
+		Alerts:      alerts,
+		Location:    weather.Location,
+	})
+}
+
+func GetMetricsV2(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Metrics],
+	resolveCache *cache.MasterCache[types.Locations],
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/v2/metrics/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/v2/metrics/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metrics, err := fetchMetrics(loc, cache, pressureCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var tendency *types.PressureTendencyV2
+	if metrics.Tendency != nil {
+		tendency = &types.PressureTendencyV2{
+			Trend:     metrics.Tendency.Trend,
+			Rate:      newMeasure(units.ConvertPressure(parseValue(metrics.Tendency.Rate), unit.Pressure), string(unit.Pressure)+"/3h"),
+			RapidFall: metrics.Tendency.RapidFall,
+		}
+	}
+
+	writeValue(res, req, types.MetricsV2{
+		Humidity:   newMeasure(parseValue(metrics.Humidity), "%"),
+		Pressure:   newMeasure(units.ConvertPressure(parseValue(metrics.Pressure), unit.Pressure), string(unit.Pressure)),
+		DewPoint:   temperatureMeasure(metrics.DewPoint, unit),
+		UvIndex:    parseValue(metrics.UvIndex),
+		Visibility: newMeasure(units.ConvertDistance(parseValue(metrics.Visibility), unit.Distance), string(unit.Distance)),
+		Tendency:   tendency,
+	})
+}
+
+func GetWindV2(
+	res http.ResponseWriter,
+	req *http.Request,
+	cache *cache.MasterCache[types.Wind],
+	resolveCache *cache.MasterCache[types.Locations],
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/v2/wind/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/v2/wind/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wind, err := fetchWind(loc, cache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeValue(res, req, windV2(wind, unit))
+}
+
+func GetForecastV2(
+	res http.ResponseWriter,
+	req *http.Request,
+	dCache *cache.MasterCache[types.DailyForecast],
+	hCache *cache.MasterCache[types.HourlyForecast],
+	resolveCache *cache.MasterCache[types.Locations],
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/v2/forecast/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/v2/forecast/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timezone, err := getTimezone(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check whether the 'h' parameter(hourly forecast) is specified
+	if req.URL.Query().Has("h") {
+		forecast, err := fetchForecast(loc, hCache, vars, model.HOURLY)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		localizeHourlyForecast(&forecast, timezone, lang)
+
+		entries := make([]types.HourlyForecastEntityV2, 0, len(forecast.Forecast))
+		for _, val := range forecast.Forecast {
+			entries = append(entries, types.HourlyForecastEntityV2{
+				Time:        val.Time.Time,
+				Temperature: temperatureMeasure(val.Temperature, unit),
+				Condition:   val.Condition,
+				Emoji:       val.Emoji,
+				Wind:        windV2(val.Wind, unit),
+				RainProb:    newMeasure(parseValue(val.RainProb), "%"),
+			})
+		}
+
+		writeValue(res, req, types.HourlyForecastV2{
+			Timezone: forecast.Timezone,
+			Forecast: entries,
+		})
+	} else { // Daily forecast(default)
+		forecast, err := fetchForecast(loc, dCache, vars, model.DAILY)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		localizeDailyForecast(&forecast, timezone, lang)
+
+		entries := make([]types.DailyForecastEntityV2, 0, len(forecast.Forecast))
+		for _, val := range forecast.Forecast {
+			entries = append(entries, types.DailyForecastEntityV2{
+				Date:      val.Date.Date.Format("2006-01-02"),
+				Min:       temperatureMeasure(val.Min, unit),
+				Max:       temperatureMeasure(val.Max, unit),
+				Condition: val.Condition,
+				Emoji:     val.Emoji,
+				FeelsLike: temperatureMeasure(val.FeelsLike, unit),
+				Wind:      windV2(val.Wind, unit),
+				RainProb:  newMeasure(parseValue(val.RainProb), "%"),
+			})
+		}
+
+		writeValue(res, req, types.DailyForecastV2{
+			Timezone: forecast.Timezone,
+			Forecast: entries,
+		})
+	}
+}
+
+func GetMoonV2(res http.ResponseWriter, req *http.Request, cache *cache.MasterCache[types.Moon], vars *types.Variables) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	lang, err := getLanguage(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	moon, err := fetchMoon(cache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeValue(res, req, types.MoonV2{
+		Icon:         moon.Icon,
+		Phase:        i18n.MoonPhase(moon.Phase, lang),
+		Illumination: newMeasure(parseValue(moon.Percentage), "%"),
+	})
+}
+
+func GetStatisticsV2(
+	res http.ResponseWriter,
+	req *http.Request,
+	resolveCache *cache.MasterCache[types.Locations],
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name from '/v2/stats/:city'(or the coordinates from the 'lat' and 'lon' parameters)
+	loc, err := getLocation(req, getCityName(req, "/v2/stats/"), resolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cityKey, err := loc.key()
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check whether the 'groupBy' parameter(weekly/monthly aggregation) is specified
+	if req.URL.Query().Has("groupBy") {
+		groupedStats, err := model.GetGroupedStatistics(cityKey, req.URL.Query().Get("groupBy"), statCache)
+		if err != nil {
+			writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		periods := make([]types.StatPeriodV2, 0, len(groupedStats.Periods))
+		for _, period := range groupedStats.Periods {
+			periods = append(periods, types.StatPeriodV2{
+				Period: period.Period,
+				From:   period.From.Date.Format("2006-01-02"),
+				To:     period.To.Date.Format("2006-01-02"),
+				Count:  period.Count,
+				Min:    temperatureMeasure(period.Min, unit),
+				Max:    temperatureMeasure(period.Max, unit),
+				Mean:   temperatureMeasure(period.Mean, unit),
+				StdDev: temperatureDeltaMeasure(period.StdDev, unit),
+				Median: temperatureMeasure(period.Median, unit),
+			})
+		}
+
+		writeValue(res, req, types.GroupedStatResultV2{
+			GroupBy: groupedStats.GroupBy,
+			Periods: periods,
+		})
+		return
+	}
+
+	confidence, err := getConfidence(req)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := model.GetStatistics(cityKey, confidence, statCache)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	anomalies := make([]types.WeatherAnomalyV2, 0)
+	if stats.Anomaly != nil {
+		for _, anomaly := range *stats.Anomaly {
+			anomalies = append(anomalies, types.WeatherAnomalyV2{
+				Date:        anomaly.Date.Date.Format("2006-01-02"),
+				Temperature: temperatureMeasure(anomaly.Temp, unit),
+			})
+		}
+	}
+
+	writeValue(res, req, types.StatResultV2{
+		Min:      temperatureMeasure(stats.Min, unit),
+		Max:      temperatureMeasure(stats.Max, unit),
+		Count:    stats.Count,
+		Mean:     temperatureMeasure(stats.Mean, unit),
+		MeanCI:   confidenceIntervalV2(stats.MeanCI, unit),
+		StdDev:   temperatureDeltaMeasure(stats.StdDev, unit),
+		Median:   temperatureMeasure(stats.Median, unit),
+		MedianCI: confidenceIntervalV2(stats.MedianCI, unit),
+		Mode:     temperatureMeasure(stats.Mode, unit),
+		Anomaly:  anomalies,
+	})
+}
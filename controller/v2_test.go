@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    types.Measure
+		Expected string
+	}{
+		{"Celsius", temperatureMeasure("18.2", units.Metric), `{"value":18.2,"unit":"°C"}`},
+		{"Fahrenheit", temperatureMeasure("18.2", units.Imperial), `{"value":64.76,"unit":"°F"}`},
+		{"Delta", temperatureDeltaMeasure("-2", units.Imperial), `{"value":-3.6,"unit":"°F"}`},
+		{"Rounded", newMeasure(1.0/3, "%"), `{"value":0.33,"unit":"%"}`},
+		{"Percentage", newMeasure(parseValue("65%"), "%"), `{"value":65,"unit":"%"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := json.Marshal(test.Input)
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if string(got) != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestTimestampV2(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	start := time.Date(2025, time.August, 29, 2, 0, 0, 0, location)
+	weather := types.WeatherV2{
+		Timestamp: time.Date(2025, time.August, 29, 12, 0, 0, 0, time.UTC).In(location),
+		Alerts:    []types.WeatherAlertV2{{Start: start, End: start.Add(22 * time.Hour)}},
+	}
+
+	got, err := json.Marshal(weather)
+	if err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	for _, expected := range []string{
+		`"timestamp":"2025-08-29T14:00:00+02:00"`,
+		`"start":"2025-08-29T02:00:00+02:00"`,
+		`"end":"2025-08-30T00:00:00+02:00"`,
+	} {
+		if !strings.Contains(string(got), expected) {
+			t.Errorf("Got %s, wanted %s", got, expected)
+		}
+	}
+}
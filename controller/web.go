@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+	"github.com/ceticamarco/zephyr/web"
+)
+
+// webSection, representing a page of a city within the web interface
+type webSection struct {
+	Path  string
+	Title string
+}
+
+var webSections = []webSection{
+	{"", "Weather"},
+	{"metrics", "Metrics"},
+	{"wind", "Wind"},
+	{"forecast", "Forecast"},
+	{"moon", "Moon"},
+	{"stats", "Statistics"},
+}
+
+// webPage, representing the data passed to the templates of the web interface
+type webPage struct {
+	Lang     i18n.Language
+	City     string
+	CityPath string
+	Units    string
+	Query    string
+	Section  string
+	Sections []webSection
+	Data     any
+}
+
+// webError, representing the content of the error page
+type webError struct {
+	Status  string
+	Message string
+}
+
+// webForecast, representing the content of the forecast page
+type webForecast struct {
+	Daily  types.DailyForecast
+	Hourly types.HourlyForecast
+}
+
+func renderPage(res http.ResponseWriter, status int, name string, page webPage) {
+	// Render the page before writing the status code, so that
+	// a template error does not produce a partial document
+	var body bytes.Buffer
+	if err := web.Render(&body, name, page); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	res.WriteHeader(status)
+	res.Write(body.Bytes())
+}
+
+func renderErrorPage(res http.ResponseWriter, status int, message string, page webPage) {
+	page.Data = webError{
+		Status:  fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Message: message,
+	}
+
+	renderPage(res, status, "error", page)
+}
+
+// Returns the query string preserved across the pages(i.e. units, timezone and language)
+func getWebQuery(req *http.Request) string {
+	query := url.Values{}
+	for _, key := range []string{"units", "tz", "lang"} {
+		if val := req.URL.Query().Get(key); val != "" {
+			query.Set(key, val)
+		}
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + query.Encode()
+}
+
+// Retrieves the data of a section of a city, formatted according to the request
+func getWebSection(
+	req *http.Request,
+	cityName string,
+	section string,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) (any, error) {
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, err := getTimezone(req)
+	if err != nil {
+		return nil, err
+	}
+
+	lang, err := getLanguage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := newLocation(cityName, &masterCache.ResolveCache, vars)
+
+	switch section {
+	case "metrics":
+		metrics, err := fetchMetrics(loc, &masterCache.MetricsCache, pressureCache, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		fmtMetrics(&metrics, unit)
+
+		return metrics, nil
+	case "wind":
+		wind, err := fetchWind(loc, &masterCache.WindCache, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		wind.Speed = fmtWind(wind.Speed, unit)
+
+		return wind, nil
+	case "forecast":
+		daily, err := fetchForecast(loc, &masterCache.DailyForecastCache, vars, model.DAILY)
+		if err != nil {
+			return nil, err
+		}
+
+		hourly, err := fetchForecast(loc, &masterCache.HourlyForecastCache, vars, model.HOURLY)
+		if err != nil {
+			return nil, err
+		}
+
+		localizeDailyForecast(&daily, timezone, lang)
+		localizeHourlyForecast(&hourly, timezone, lang)
+		fmtDailyForecast(&daily, unit)
+		fmtHourlyForecast(&hourly, unit)
+
+		return webForecast{Daily: daily, Hourly: hourly}, nil
+	case "moon":
+		moon, err := fetchMoon(&masterCache.MoonCache, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		moon.Phase = i18n.MoonPhase(moon.Phase, lang)
+		moon.Percentage = fmt.Sprintf("%s%%", moon.Percentage)
+
+		return moon, nil
+	case "stats":
+		cityKey, err := loc.key()
+		if err != nil {
+			return nil, err
+		}
+
+		stats, err := model.GetStatistics(cityKey, 0.95, statCache)
+		if err != nil {
+			return nil, err
+		}
+
+		// Hide the anomalies section when none has been detected
+		if stats.Anomaly != nil && len(*stats.Anomaly) == 0 {
+			stats.Anomaly = nil
+		}
+
+		localizeStatistics(&stats, lang)
+		fmtStatistics(&stats, unit)
+
+		return stats, nil
+	default: // Current weather
+		weather, err := fetchWeather(loc, lang, &masterCache.WeatherCache, statCache, true, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		localizeWeather(&weather, timezone, lang)
+		fmtWeather(&weather, unit)
+
+		return weather, nil
+	}
+}
+
+func GetWebPage(
+	res http.ResponseWriter,
+	req *http.Request,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	pressureCache *cache.PressureCache,
+	vars *types.Variables,
+) {
+	page := webPage{
+		Lang:     i18n.English,
+		Units:    strings.ToLower(req.URL.Query().Get("units")),
+		Query:    getWebQuery(req),
+		Sections: webSections,
+	}
+
+	if lang, err := getLanguage(req); err == nil {
+		page.Lang = lang
+	}
+
+	if req.Method != http.MethodGet {
+		renderErrorPage(res, http.StatusMethodNotAllowed, "method not allowed", page)
+		return
+	}
+
+	// Search form, redirect to '/city/:city'
+	if req.URL.Path == "/" {
+		cityName := strings.TrimSpace(req.URL.Query().Get("city"))
+		if cityName == "" {
+			renderPage(res, http.StatusOK, "index", page)
+			return
+		}
+
+		http.Redirect(res, req, "/city/"+url.PathEscape(cityName)+page.Query, http.StatusSeeOther)
+		return
+	}
+
+	// Extract city name and section from '/city/:city/:section'
+	cityName, section, _ := strings.Cut(strings.Trim(strings.TrimPrefix(req.URL.Path, "/city/"), "/"), "/")
+	isSection := func(val webSection) bool { return val.Path == section }
+	if !strings.HasPrefix(req.URL.Path, "/city/") || cityName == "" || !slices.ContainsFunc(webSections, isSection) {
+		renderErrorPage(res, http.StatusNotFound, "page not found", page)
+		return
+	}
+
+	page.City = cityName
+	page.CityPath = url.PathEscape(cityName)
+	page.Section = section
+
+	data, err := getWebSection(req, cityName, section, masterCache, statCache, pressureCache, vars)
+	if err != nil {
+		renderErrorPage(res, http.StatusBadRequest, err.Error(), page)
+		return
+	}
+
+	// The root section of a city is the current weather
+	name := section
+	if name == "" {
+		name = "weather"
+	}
+
+	page.Data = data
+	renderPage(res, http.StatusOK, name, page)
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/web"
+)
+
+func TestRenderPages(t *testing.T) {
+	date := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	wind := types.Wind{Arrow: "↗", Direction: "SW", Speed: "12 km/h"}
+	ci := &types.ConfidenceInterval{Level: "95%", Lower: "14.24°C", Upper: "16.80°C"}
+
+	// Populated data of each page, the zero value is rendered as well
+	data := map[string]any{
+		"index": nil,
+		"error": webError{Status: "404 Not Found", Message: "page not found"},
+		"weather": types.Weather{
+			Date:        types.ZephyrDate{Date: date, Lang: i18n.Italian},
+			Timezone:    "Europe/Rome",
+			Temperature: "21°C",
+			Min:         "15°C",
+			Max:         "24°C",
+			Condition:   "Sereno",
+			FeelsLike:   "21°C",
+			Emoji:       "☀️",
+			Alerts: []types.WeatherAlert{{
+				Event:       "Thunderstorms",
+				Start:       types.ZephyrAlertDate{Date: date},
+				End:         types.ZephyrAlertDate{Date: date.Add(time.Hour)},
+				Description: "Severe thunderstorms",
+			}},
+			Location: &types.City{Name: "Milano", State: "Lombardia", Country: "IT"},
+		},
+		"metrics": types.Metrics{
+			Humidity:   "60%",
+			Pressure:   "1013 hPa",
+			DewPoint:   "12°C",
+			UvIndex:    "5",
+			Visibility: "10km",
+			Tendency:   &types.PressureTendency{Trend: "falling", Rate: "-2 hPa/3h", RapidFall: true},
+		},
+		"wind": wind,
+		"forecast": webForecast{
+			Daily: types.DailyForecast{
+				Timezone: "Europe/Rome",
+				Forecast: []types.DailyForecastEntity{{
+					Date: types.ZephyrDate{Date: date}, Min: "15°C", Max: "24°C", Condition: "Clear",
+					Emoji: "☀️", FeelsLike: "22°C", Wind: wind, RainProb: "10%",
+				}},
+			},
+			Hourly: types.HourlyForecast{
+				Timezone: "Europe/Rome",
+				Forecast: []types.HourlyForecastEntity{{
+					Time: types.ZephyrTime{Time: date}, Temperature: "21°C", Condition: "Clear",
+					Emoji: "☀️", Wind: wind, RainProb: "0%",
+				}},
+			},
+		},
+		"moon": types.Moon{Icon: "🌕", Phase: "Full Moon", Percentage: "100%"},
+		"stats": types.StatResult{
+			Min: "10°C", Max: "30°C", Count: 10, Mean: "20°C", MeanCI: ci, StdDev: "2°C",
+			Median: "20°C", MedianCI: ci, Mode: "21°C",
+			Anomaly: &[]types.WeatherAnomaly{{Date: types.ZephyrDate{Date: date}, Temp: "30°C"}},
+		},
+	}
+
+	// Each template, besides the layout, is a page
+	templates, err := filepath.Glob("../web/templates/*.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, template := range templates {
+		name := strings.TrimSuffix(filepath.Base(template), ".html")
+		if name == "layout" {
+			continue
+		}
+
+		populated, found := data[name]
+		if !found {
+			t.Fatalf("Missing data of page '%s'", name)
+		}
+
+		var zero any
+		if populated != nil {
+			zero = reflect.Zero(reflect.TypeOf(populated)).Interface()
+		}
+
+		tests := []struct {
+			Name string
+			Page webPage
+		}{
+			{"Zero value", webPage{Data: zero}},
+			{"Populated", webPage{
+				Lang:     i18n.Italian,
+				City:     "Milan",
+				CityPath: "Milan",
+				Units:    "metric",
+				Query:    "?lang=it",
+				Section:  "forecast",
+				Sections: webSections,
+				Data:     populated,
+			}},
+		}
+
+		for _, test := range tests {
+			t.Run(name+"/"+test.Name, func(t *testing.T) {
+				var body bytes.Buffer
+				if err := web.Render(&body, name, test.Page); err != nil {
+					t.Fatalf("Got %s, wanted no error", err)
+				}
+
+				if !strings.Contains(body.String(), "</html>") {
+					t.Errorf("Got a partial document, wanted a complete one")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"image/png"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/i18n"
+	"github.com/ceticamarco/zephyr/model"
+	"github.com/ceticamarco/zephyr/render"
+	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/units"
+)
+
+func newWidget(
+	cityName string,
+	weather types.Weather,
+	daily types.DailyForecast,
+	hourly types.HourlyForecast,
+	unit units.System,
+	lang i18n.Language,
+) render.Widget {
+	widget := render.Widget{
+		City:        cityName,
+		Date:        i18n.Format(weather.Date.Date, "Monday, 2006/01/02", lang),
+		Glyph:       render.GlyphFromEmoji(weather.Emoji),
+		Temperature: fmtTemperature(weather.Temperature, unit),
+		Condition:   weather.Condition,
+		Min:         fmtTemperature(weather.Min, unit),
+		Max:         fmtTemperature(weather.Max, unit),
+	}
+
+	for _, val := range daily.Forecast {
+		// Abbreviate the weekday name
+		label := []rune(i18n.Format(val.Date.Date, "Monday", lang))
+
+		widget.Days = append(widget.Days, render.WidgetDay{
+			Label: string(label[:min(len(label), 3)]),
+			Glyph: render.GlyphFromEmoji(val.Emoji),
+			Min:   fmtTemperature(val.Min, unit),
+			Max:   fmtTemperature(val.Max, unit),
+		})
+	}
+
+	for _, val := range hourly.Forecast {
+		// The shape of the sparkline does not depend on the unit,
+		// therefore temperatures are kept in Celsius
+		temp, _ := strconv.ParseFloat(val.Temperature, 64)
+		widget.Hourly = append(widget.Hourly, temp)
+	}
+
+	if len(widget.Hourly) > 0 {
+		lowest, highest := slices.Min(widget.Hourly), slices.Max(widget.Hourly)
+		widget.HourlyMin = fmtTemperature(strconv.FormatFloat(lowest, 'f', -1, 64), unit)
+		widget.HourlyMax = fmtTemperature(strconv.FormatFloat(highest, 'f', -1, 64), unit)
+	}
+
+	return widget
+}
+
+// Retrieves the resources of a city and formats them as a widget
+func getWidget(
+	req *http.Request,
+	loc *location,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) (render.Widget, error) {
+	unit, err := units.FromQuery(req.URL.Query())
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	timezone, err := getTimezone(req)
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	lang, err := getLanguage(req)
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	weather, err := fetchWeather(loc, lang, &masterCache.WeatherCache, statCache, true, vars)
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	daily, err := fetchForecast(loc, &masterCache.DailyForecastCache, vars, model.DAILY)
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	hourly, err := fetchForecast(loc, &masterCache.HourlyForecastCache, vars, model.HOURLY)
+	if err != nil {
+		return render.Widget{}, err
+	}
+
+	localizeWeather(&weather, timezone, lang)
+	localizeDailyForecast(&daily, timezone, lang)
+	localizeHourlyForecast(&hourly, timezone, lang)
+
+	return newWidget(loc.name, weather, daily, hourly, unit, lang), nil
+}
+
+// Extracts city name and file extension from '<prefix>:city.<extension>'
+func getImageName(req *http.Request, prefix string) (string, string) {
+	fileName := getCityName(req, prefix)
+	extension := path.Ext(fileName)
+
+	return strings.TrimSuffix(fileName, extension), extension
+}
+
+func GetWidget(
+	res http.ResponseWriter,
+	req *http.Request,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name and image format from '/widget/:city.(svg|png)'. The city
+	// name is omitted when the coordinates are specified(e.g. '/widget/.svg?lat=&lon=')
+	cityName, extension := getImageName(req, "/widget/")
+	loc, err := getLocation(req, cityName, &masterCache.ResolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if extension != ".svg" && extension != ".png" {
+		writeError(res, req, "error", "unsupported image format, use either '.svg' or '.png'", http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the size('WxH') and the theme('light' or 'dark') of the widget
+	width, height, err := render.ParseSize(req.URL.Query().Get("size"), 400, 240)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	theme, err := render.ParseTheme(req.URL.Query().Get("theme"))
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	widget, err := getWidget(req, loc, masterCache, statCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if extension == ".svg" {
+		svg := render.NewSVG(width, height)
+		render.DrawWidget(svg, widget, width, height, theme)
+
+		res.Header().Set("Content-Type", "image/svg+xml")
+		res.WriteHeader(http.StatusOK)
+		res.Write(svg.Bytes())
+		return
+	}
+
+	raster := render.NewRaster(width, height)
+	render.DrawWidget(raster, widget, width, height, theme)
+
+	res.Header().Set("Content-Type", "image/png")
+	res.WriteHeader(http.StatusOK)
+	png.Encode(res, raster.Image)
+}
+
+func GetEinkDisplay(
+	res http.ResponseWriter,
+	req *http.Request,
+	masterCache *cache.MasterCaches,
+	statCache *cache.StatCache,
+	vars *types.Variables,
+) {
+	if req.Method != http.MethodGet {
+		writeError(res, req, "error", "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract city name and image format from '/eink/:city.(bmp|pbm|bin)'. The city
+	// name is omitted when the coordinates are specified(e.g. '/eink/.bmp?lat=&lon=')
+	cityName, extension := getImageName(req, "/eink/")
+	loc, err := getLocation(req, cityName, &masterCache.ResolveCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	contentType, found := map[string]string{
+		".bmp": "image/bmp",
+		".pbm": "image/x-portable-bitmap",
+		".bin": "application/octet-stream",
+	}[extension]
+	if !found {
+		writeError(res, req, "error", "unsupported image format, use one of '.bmp', '.pbm' or '.bin'", http.StatusBadRequest)
+		return
+	}
+
+	// Retrieve the resolution('WxH') of the display
+	width, height, err := render.ParseSize(req.URL.Query().Get("size"), 296, 128)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The 'dark' theme draws white on black
+	theme := render.EinkTheme
+	switch req.URL.Query().Get("theme") {
+	case "", "light":
+	case "dark":
+		theme = render.InvertTheme(render.EinkTheme)
+	default:
+		writeError(res, req, "error", "unsupported theme, use either 'light' or 'dark'", http.StatusBadRequest)
+		return
+	}
+
+	widget, err := getWidget(req, loc, masterCache, statCache, vars)
+	if err != nil {
+		writeError(res, req, "error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	raster := render.NewRaster(width, height)
+	render.DrawWidget(raster, widget, width, height, theme)
+	bitmap := render.Dither(raster.Image)
+
+	res.Header().Set("Content-Type", contentType)
+	res.Header().Set("X-Bitmap-Width", strconv.Itoa(width))
+	res.Header().Set("X-Bitmap-Height", strconv.Itoa(height))
+	res.WriteHeader(http.StatusOK)
+
+	switch extension {
+	case ".bmp":
+		bitmap.EncodeBMP(res)
+	case ".pbm":
+		bitmap.EncodePBM(res)
+	default:
+		res.Write(bitmap.Packed())
+	}
+}
@@ -0,0 +1,33 @@
+package geonames
+
+import "math"
+
+// Mean radius of the Earth in kilometers
+const earthRadius = 6371.0
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// Returns the great-circle distance in kilometers between two points(i.e. the haversine formula)
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLon := radians(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Returns the initial bearing from the first point to the second one, expressed
+// in degrees clockwise from the north(e.g. 90 means that the second point lies east)
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	dLon := radians(lon2 - lon1)
+
+	y := math.Sin(dLon) * math.Cos(radians(lat2))
+	x := math.Cos(radians(lat1))*math.Sin(radians(lat2)) -
+		math.Sin(radians(lat1))*math.Cos(radians(lat2))*math.Cos(dLon)
+
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
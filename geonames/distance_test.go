@@ -0,0 +1,57 @@
+package geonames
+
+import (
+	"math"
+	"testing"
+)
+
+type TestEntry struct {
+	Name     string
+	From     [2]float64
+	To       [2]float64
+	Expected float64
+}
+
+func cmpVal(x, y float64) bool {
+	const epsilon = 1e-6
+
+	return math.Abs(x-y) < epsilon
+}
+
+func TestDistance(t *testing.T) {
+	tests := []TestEntry{
+		{"Same point", [2]float64{45.46427, 9.18951}, [2]float64{45.46427, 9.18951}, 0},
+		{"Milan to Rome", [2]float64{45.46427, 9.18951}, [2]float64{41.89193, 12.51133}, 478.5981536825773},
+		{"Milan to Monza", [2]float64{45.46427, 9.18951}, [2]float64{45.58005, 9.27246}, 14.405066246339159},
+		{"Antipodes", [2]float64{0, 0}, [2]float64{0, 180}, 20015.086796020572},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := Distance(test.From[0], test.From[1], test.To[0], test.To[1])
+			if !cmpVal(got, test.Expected) {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []TestEntry{
+		{"North", [2]float64{0, 0}, [2]float64{1, 0}, 0},
+		{"East", [2]float64{0, 0}, [2]float64{0, 1}, 90},
+		{"South", [2]float64{0, 0}, [2]float64{-1, 0}, 180},
+		{"West", [2]float64{0, 0}, [2]float64{0, -1}, 270},
+		{"Milan to Rome", [2]float64{45.46427, 9.18951}, [2]float64{41.89193, 12.51133}, 144.91917795239658},
+		{"Milan to Como", [2]float64{45.46427, 9.18951}, [2]float64{45.80819, 9.0832}, 347.84195457469554},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := Bearing(test.From[0], test.From[1], test.To[0], test.To[1])
+			if !cmpVal(got, test.Expected) {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
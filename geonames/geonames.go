@@ -0,0 +1,329 @@
+package geonames
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Columns of a GeoNames dump(e.g. 'cities15000.txt')
+const (
+	colName           = 1
+	colASCIIName      = 2
+	colAlternateNames = 3
+	colLatitude       = 4
+	colLongitude      = 5
+	colCountry        = 8
+	colAdmin1         = 10
+	colPopulation     = 14
+	numColumns        = 15
+)
+
+// Match quality of a name, lower is better
+const (
+	matchExact = iota
+	matchPrefix
+	matchFuzzy
+	matchNone
+)
+
+// Place, representing an entry of a GeoNames dump
+type Place struct {
+	City       types.City
+	Admin1     string
+	Population int64
+	names      []string // Normalized name, ASCII name and alternate names
+}
+
+// Index, representing an in-memory GeoNames dump. Places can be added
+// at runtime(e.g. the resolved locations)
+type Index struct {
+	mutex  sync.RWMutex
+	places []Place
+}
+
+// Parses a tab-separated GeoNames dump. Empty lines and comments are skipped
+func Load(r io.Reader) (*Index, error) {
+	index := &Index{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Alternate names can be quite long
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		place, err := parsePlace(strings.Split(text, "\t"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		index.places = append(index.places, place)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// Parses the GeoNames dump at the given path
+func LoadFile(path string) (*Index, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+func parsePlace(columns []string) (Place, error) {
+	if len(columns) < numColumns {
+		return Place{}, fmt.Errorf("expected at least %d columns, got %d", numColumns, len(columns))
+	}
+
+	lat, err := strconv.ParseFloat(columns[colLatitude], 64)
+	if err != nil {
+		return Place{}, fmt.Errorf("invalid latitude '%s'", columns[colLatitude])
+	}
+
+	lon, err := strconv.ParseFloat(columns[colLongitude], 64)
+	if err != nil {
+		return Place{}, fmt.Errorf("invalid longitude '%s'", columns[colLongitude])
+	}
+
+	// Population is missing on some entries
+	population, _ := strconv.ParseInt(columns[colPopulation], 10, 64)
+
+	names := []string{columns[colName], columns[colASCIIName]}
+	names = append(names, strings.Split(columns[colAlternateNames], ",")...)
+
+	return Place{
+		City: types.City{
+			Name:    columns[colName],
+			Country: columns[colCountry],
+			Lat:     lat,
+			Lon:     lon,
+		},
+		Admin1:     columns[colAdmin1],
+		Population: population,
+		names:      placeNames(names...),
+	}, nil
+}
+
+// Returns the maximum edit distance tolerated by a fuzzy match, short
+// queries must match exactly(or by prefix)
+func maxDistance(query string) int {
+	switch length := len([]rune(query)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Returns the names of a place, normalized and without duplicates
+func placeNames(names ...string) []string {
+	for idx := range names {
+		names[idx] = Normalize(names[idx])
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	return slices.DeleteFunc(names, func(name string) bool { return name == "" })
+}
+
+// Returns the best match quality of a query against the names of a place. Partial
+// queries(i.e. type-ahead) are compared by edit distance against the beginning of the names
+func (place *Place) match(query string, maxDist int, partial bool) int {
+	length := len([]rune(query))
+
+	best := matchNone
+	for _, name := range place.names {
+		switch {
+		case name == query:
+			return matchExact
+		case strings.HasPrefix(name, query):
+			best = min(best, matchPrefix)
+		case best == matchNone && maxDist > 0:
+			runes := []rune(name)
+			if partial && len(runes) > length {
+				runes = runes[:length]
+			}
+
+			// Names whose length differs too much cannot match
+			diff := len(runes) - length
+			if diff <= maxDist && diff >= -maxDist && distance(string(runes), query) <= maxDist {
+				best = matchFuzzy
+			}
+		}
+	}
+
+	return best
+}
+
+// Adds a place to the index, unless a place with the same
+// name already exists in the same country at the same coordinates
+func (index *Index) Add(city types.City) {
+	const epsilon = 0.1 // About 10 km
+
+	names := []string{city.Name}
+	for _, name := range city.LocalNames {
+		names = append(names, name)
+	}
+
+	place := Place{
+		City: types.City{
+			Name:    city.Name,
+			State:   city.State,
+			Country: city.Country,
+			Lat:     city.Lat,
+			Lon:     city.Lon,
+		},
+		names: placeNames(names...),
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	for idx := range index.places {
+		known := &index.places[idx]
+		if strings.EqualFold(known.City.Country, city.Country) &&
+			math.Abs(known.City.Lat-city.Lat) < epsilon && math.Abs(known.City.Lon-city.Lon) < epsilon &&
+			known.match(Normalize(city.Name), 0, false) == matchExact {
+			return
+		}
+	}
+
+	index.places = append(index.places, place)
+}
+
+// Retrieves the places matching a query in the 'city,state,country' format, where state is
+// either the GeoNames admin1 code or the state name and country is the ISO 3166 code. Names are
+// matched exactly, by prefix and then by edit distance, ties are ranked by population
+func (index *Index) Search(query string, limit int) []types.City {
+	return index.find(query, limit, false)
+}
+
+// Retrieves the suggestions of a partial query(e.g. 'mil'), ranked like the search results
+func (index *Index) Autocomplete(query string, limit int) []types.City {
+	return index.find(query, limit, true)
+}
+
+func (index *Index) find(query string, limit int, partial bool) []types.City {
+	parts := strings.Split(query, ",")
+	for idx := range parts {
+		parts[idx] = strings.TrimSpace(parts[idx])
+	}
+
+	name := Normalize(parts[0])
+	if name == "" || limit < 1 {
+		return []types.City{}
+	}
+
+	var state, country string
+	switch {
+	case len(parts) == 2:
+		country = parts[1]
+	case len(parts) > 2:
+		state, country = parts[1], parts[2]
+	}
+
+	type candidate struct {
+		place   *Place
+		quality int
+	}
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	maxDist := maxDistance(name)
+	candidates := []candidate{}
+	for idx := range index.places {
+		place := &index.places[idx]
+		if country != "" && !strings.EqualFold(place.City.Country, country) {
+			continue
+		}
+
+		if state != "" && !strings.EqualFold(place.Admin1, state) && !strings.EqualFold(place.City.State, state) {
+			continue
+		}
+
+		if quality := place.match(name, maxDist, partial); quality != matchNone {
+			candidates = append(candidates, candidate{place, quality})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(x, y candidate) int {
+		if x.quality != y.quality {
+			return x.quality - y.quality
+		}
+
+		return cmp.Compare(y.place.Population, x.place.Population)
+	})
+
+	cities := make([]types.City, 0, min(len(candidates), limit))
+	for _, candidate := range candidates[:min(len(candidates), limit)] {
+		cities = append(cities, candidate.place.City)
+	}
+
+	return cities
+}
+
+// Neighbour, representing a place near a location along with its distance(in kilometers)
+// and its bearing(in degrees) from the location
+type Neighbour struct {
+	City     types.City
+	Distance float64
+	Bearing  float64
+}
+
+// Retrieves the populated places within a radius(in kilometers) of a location, sorted by distance.
+// Places closer than a kilometer are the location itself, hence they are skipped
+func (index *Index) Nearby(lat, lon, radius float64, limit int) []Neighbour {
+	const minDistance = 1.0
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	neighbours := []Neighbour{}
+	for idx := range index.places {
+		place := &index.places[idx]
+		if place.Population <= 0 {
+			continue
+		}
+
+		distance := Distance(lat, lon, place.City.Lat, place.City.Lon)
+		if distance < minDistance || distance > radius {
+			continue
+		}
+
+		neighbours = append(neighbours, Neighbour{
+			City:     place.City,
+			Distance: distance,
+			Bearing:  Bearing(lat, lon, place.City.Lat, place.City.Lon),
+		})
+	}
+
+	slices.SortFunc(neighbours, func(x, y Neighbour) int {
+		return cmp.Compare(x.Distance, y.Distance)
+	})
+
+	return neighbours[:min(len(neighbours), max(limit, 0))]
+}
@@ -0,0 +1,201 @@
+package geonames
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Excerpt of a GeoNames dump, alternate names are shortened
+const dump = `3173435	Milan	Milan	Milano,Milán,Mailand	45.46427	9.18951	P	PPLA	IT		09	MI	015146		1371498		122	Europe/Rome	2024-01-01
+4250542	Springfield	Springfield		39.80172	-89.64371	P	PPLA	US		IL	167			114394	182	181	America/Chicago	2024-01-01
+4409896	Springfield	Springfield		37.21533	-93.29824	P	PPLA2	US		MO	077			169176	395	398	America/Chicago	2024-01-01
+2980291	Saint-Étienne	Saint-Etienne	Sant-Etiève	45.43389	4.39	P	PPLA3	FR		84	42	421		171057		520	Europe/Paris	2024-01-01
+3172629	Monza	Monza		45.58005	9.27246	P	PPLA2	IT		09	MB	108033		120204		162	Europe/Rome	2024-01-01
+3178229	Como	Como		45.80819	9.0832	P	PPLA2	IT		09	CO	013075		84326		201	Europe/Rome	2024-01-01
+3173529	Milazzo	Milazzo		38.22008	15.24023	P	PPL	IT		15	ME	083049		31797		6	Europe/Rome	2024-01-01
+
+# Comments and empty lines are skipped
+`
+
+func names(cities []types.City) string {
+	var parts []string
+	for _, city := range cities {
+		parts = append(parts, city.Name+","+city.Country)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func TestSearch(t *testing.T) {
+	index, err := Load(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Cannot load the dump: %v", err)
+	}
+
+	tests := []struct {
+		Name     string
+		Query    string
+		Limit    int
+		Expected string
+	}{
+		{"Exact name", "Milan", 5, "Milan,IT"},
+		{"Alternate name", "milano", 5, "Milan,IT"},
+		{"Prefix ranked by population", "mil", 5, "Milan,IT;Milazzo,IT"},
+		{"Exact before prefix", "milazzo", 5, "Milazzo,IT"},
+		{"Fuzzy", "Milen", 5, "Milan,IT"},
+		{"Diacritics", "saint etienne", 5, "Saint-Étienne,FR"},
+		{"Ranked by population", "Springfield", 5, "Springfield,US;Springfield,US"},
+		{"Limit", "Springfield", 1, "Springfield,US"},
+		{"Country", "Milan,FR", 5, ""},
+		{"State and country", "Springfield,il,us", 5, "Springfield,US"},
+		{"Unknown", "Zephyr", 5, ""},
+		{"Empty", " ,IT", 5, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := names(index.Search(test.Query, test.Limit))
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+
+	// Springfield, MO is more populated than Springfield, IL
+	got := index.Search("Springfield", 1)
+	if len(got) != 1 || got[0].Lat != 37.21533 {
+		t.Errorf("Got %v, wanted Springfield, MO", got)
+	}
+}
+
+func TestAutocomplete(t *testing.T) {
+	index, err := Load(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Cannot load the dump: %v", err)
+	}
+
+	// Resolved locations, the first one is already known
+	index.Add(types.City{Name: "Milan", Country: "IT", Lat: 45.4642, Lon: 9.19})
+	index.Add(types.City{Name: "Zurich", Country: "CH", Lat: 47.3769, Lon: 8.5417, LocalNames: map[string]string{"de": "Zürich"}})
+
+	tests := []struct {
+		Name     string
+		Query    string
+		Limit    int
+		Expected string
+	}{
+		{"Prefix ranked by population", "mi", 5, "Milan,IT;Milazzo,IT"},
+		{"Typo", "milxz", 5, "Milazzo,IT"},
+		{"Diacritics", "sant etie", 5, "Saint-Étienne,FR"},
+		{"Resolved location", "zür", 5, "Zurich,CH"},
+		{"Known location", "milan", 5, "Milan,IT;Milazzo,IT"},
+		{"Country", "spring,us", 1, "Springfield,US"},
+		{"Unknown", "xyz", 5, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := names(index.Autocomplete(test.Query, test.Limit))
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestNearby(t *testing.T) {
+	index, err := Load(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Cannot load the dump: %v", err)
+	}
+
+	// Resolved locations are not populated places
+	index.Add(types.City{Name: "Sesto San Giovanni", Country: "IT", Lat: 45.5358, Lon: 9.2346})
+
+	tests := []struct {
+		Name     string
+		Radius   float64
+		Limit    int
+		Expected string
+	}{
+		{"Sorted by distance", 50, 10, "Monza,IT;Como,IT"},
+		{"Radius", 20, 10, "Monza,IT"},
+		{"Limit", 50, 1, "Monza,IT"},
+		{"None", 5, 10, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var cities []types.City
+			for _, neighbour := range index.Nearby(45.4642, 9.19, test.Radius, test.Limit) {
+				cities = append(cities, neighbour.City)
+			}
+
+			if got := names(cities); got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+	}{
+		{"Missing columns", "3173435\tMilan\tMilan"},
+		{"Invalid latitude", strings.Replace(dump, "45.46427", "north", 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(test.Input)); err == nil {
+				t.Errorf("Got nil, wanted an error")
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{"Lowercase", "Milan", "milan"},
+		{"Diacritics", "Zürich", "zurich"},
+		{"Punctuation", "Saint-Étienne", "saint etienne"},
+		{"Spaces", "  New   York ", "new york"},
+		{"Ligature", "Straße", "strasse"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := Normalize(test.Input); got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		X, Y     string
+		Expected int
+	}{
+		{"milan", "milan", 0},
+		{"milan", "milna", 2},
+		{"milan", "milano", 1},
+		{"", "rome", 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.X+"/"+test.Y, func(t *testing.T) {
+			if got := distance(test.X, test.Y); got != test.Expected {
+				t.Errorf("Got %d, wanted %d", got, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package geonames
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Latin letters with diacritics are matched without them
+var diacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s",
+	'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Normalizes a name for matching, that is, lowercases it, removes the diacritics
+// and replaces punctuation with single spaces(e.g. 'Saint-Étienne' becomes 'saint etienne')
+func Normalize(name string) string {
+	var builder strings.Builder
+
+	space := false
+	for _, r := range strings.ToLower(name) {
+		base, found := diacritics[r]
+		if !found && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			space = builder.Len() > 0
+			continue
+		}
+
+		if space {
+			builder.WriteByte(' ')
+			space = false
+		}
+
+		if found {
+			builder.WriteString(base)
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// Returns the Levenshtein distance between two strings
+func distance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(y)]
+}
@@ -0,0 +1,158 @@
+package i18n
+
+var catalogs = map[Language]catalog{
+	English: {
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months: [12]string{
+			"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December",
+		},
+		timeLayout: "3:04 PM",
+		conditions: map[string]string{},
+		moonPhases: map[string]string{},
+	},
+	Italian: {
+		weekdays: [7]string{"Domenica", "Lunedì", "Martedì", "Mercoledì", "Giovedì", "Venerdì", "Sabato"},
+		months: [12]string{
+			"Gennaio", "Febbraio", "Marzo", "Aprile", "Maggio", "Giugno",
+			"Luglio", "Agosto", "Settembre", "Ottobre", "Novembre", "Dicembre",
+		},
+		timeLayout: "15:04",
+		conditions: map[string]string{
+			"Thunderstorm": "Temporale",
+			"Drizzle":      "Pioviggine",
+			"Rain":         "Pioggia",
+			"Snow":         "Neve",
+			"Mist":         "Foschia",
+			"Smoke":        "Fumo",
+			"Haze":         "Caligine",
+			"Dust":         "Polvere",
+			"Fog":          "Nebbia",
+			"Sand":         "Sabbia",
+			"Ash":          "Cenere",
+			"Squall":       "Burrasca",
+			"Tornado":      "Tornado",
+			"Clear":        "Sereno",
+			"Clouds":       "Nuvoloso",
+		},
+		moonPhases: map[string]string{
+			"New Moon":           "Luna nuova",
+			"Waxing Crescent":    "Luna crescente",
+			"First Quarter":      "Primo quarto",
+			"Waxing Gibbous":     "Gibbosa crescente",
+			"Full Moon":          "Luna piena",
+			"Waning Gibbous":     "Gibbosa calante",
+			"Last Quarter":       "Ultimo quarto",
+			"Waning Crescent":    "Luna calante",
+			"Unknown moon phase": "Fase lunare sconosciuta",
+		},
+	},
+	German: {
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		months: [12]string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		timeLayout: "15:04",
+		conditions: map[string]string{
+			"Thunderstorm": "Gewitter",
+			"Drizzle":      "Nieselregen",
+			"Rain":         "Regen",
+			"Snow":         "Schnee",
+			"Mist":         "Dunst",
+			"Smoke":        "Rauch",
+			"Haze":         "Diesig",
+			"Dust":         "Staub",
+			"Fog":          "Nebel",
+			"Sand":         "Sand",
+			"Ash":          "Asche",
+			"Squall":       "Böen",
+			"Tornado":      "Tornado",
+			"Clear":        "Klar",
+			"Clouds":       "Bewölkt",
+		},
+		moonPhases: map[string]string{
+			"New Moon":           "Neumond",
+			"Waxing Crescent":    "Zunehmende Sichel",
+			"First Quarter":      "Erstes Viertel",
+			"Waxing Gibbous":     "Zunehmender Mond",
+			"Full Moon":          "Vollmond",
+			"Waning Gibbous":     "Abnehmender Mond",
+			"Last Quarter":       "Letztes Viertel",
+			"Waning Crescent":    "Abnehmende Sichel",
+			"Unknown moon phase": "Unbekannte Mondphase",
+		},
+	},
+	French: {
+		weekdays: [7]string{"Dimanche", "Lundi", "Mardi", "Mercredi", "Jeudi", "Vendredi", "Samedi"},
+		months: [12]string{
+			"Janvier", "Février", "Mars", "Avril", "Mai", "Juin",
+			"Juillet", "Août", "Septembre", "Octobre", "Novembre", "Décembre",
+		},
+		timeLayout: "15:04",
+		conditions: map[string]string{
+			"Thunderstorm": "Orage",
+			"Drizzle":      "Bruine",
+			"Rain":         "Pluie",
+			"Snow":         "Neige",
+			"Mist":         "Brume",
+			"Smoke":        "Fumée",
+			"Haze":         "Brume sèche",
+			"Dust":         "Poussière",
+			"Fog":          "Brouillard",
+			"Sand":         "Sable",
+			"Ash":          "Cendres",
+			"Squall":       "Grains",
+			"Tornado":      "Tornade",
+			"Clear":        "Dégagé",
+			"Clouds":       "Nuageux",
+		},
+		moonPhases: map[string]string{
+			"New Moon":           "Nouvelle lune",
+			"Waxing Crescent":    "Premier croissant",
+			"First Quarter":      "Premier quartier",
+			"Waxing Gibbous":     "Gibbeuse croissante",
+			"Full Moon":          "Pleine lune",
+			"Waning Gibbous":     "Gibbeuse décroissante",
+			"Last Quarter":       "Dernier quartier",
+			"Waning Crescent":    "Dernier croissant",
+			"Unknown moon phase": "Phase lunaire inconnue",
+		},
+	},
+	Spanish: {
+		weekdays: [7]string{"Domingo", "Lunes", "Martes", "Miércoles", "Jueves", "Viernes", "Sábado"},
+		months: [12]string{
+			"Enero", "Febrero", "Marzo", "Abril", "Mayo", "Junio",
+			"Julio", "Agosto", "Septiembre", "Octubre", "Noviembre", "Diciembre",
+		},
+		timeLayout: "15:04",
+		conditions: map[string]string{
+			"Thunderstorm": "Tormenta",
+			"Drizzle":      "Llovizna",
+			"Rain":         "Lluvia",
+			"Snow":         "Nieve",
+			"Mist":         "Neblina",
+			"Smoke":        "Humo",
+			"Haze":         "Calima",
+			"Dust":         "Polvo",
+			"Fog":          "Niebla",
+			"Sand":         "Arena",
+			"Ash":          "Ceniza",
+			"Squall":       "Turbonada",
+			"Tornado":      "Tornado",
+			"Clear":        "Despejado",
+			"Clouds":       "Nublado",
+		},
+		moonPhases: map[string]string{
+			"New Moon":           "Luna nueva",
+			"Waxing Crescent":    "Luna creciente",
+			"First Quarter":      "Cuarto creciente",
+			"Waxing Gibbous":     "Gibosa creciente",
+			"Full Moon":          "Luna llena",
+			"Waning Gibbous":     "Gibosa menguante",
+			"Last Quarter":       "Cuarto menguante",
+			"Waning Crescent":    "Luna menguante",
+			"Unknown moon phase": "Fase lunar desconocida",
+		},
+	},
+}
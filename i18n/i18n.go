@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Language, representing an ISO 639-1 language code
+type Language string
+
+const (
+	English Language = "en"
+	Italian Language = "it"
+	German  Language = "de"
+	French  Language = "fr"
+	Spanish Language = "es"
+)
+
+// catalog, representing the translations of a language
+type catalog struct {
+	weekdays   [7]string // Starting from Sunday, like time.Weekday
+	months     [12]string
+	timeLayout string
+	conditions map[string]string
+	moonPhases map[string]string
+}
+
+func (lang Language) catalog() catalog {
+	if cat, found := catalogs[lang]; found {
+		return cat
+	}
+
+	return catalogs[English]
+}
+
+func Parse(lang string) (Language, error) {
+	// Ignore the region subtag(e.g. 'it-CH')
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(lang)), "-")
+	if _, found := catalogs[Language(base)]; !found {
+		return "", errors.New("unsupported language, use one of 'en', 'it', 'de', 'fr' or 'es'")
+	}
+
+	return Language(base), nil
+}
+
+// Selects the language of a response
+//
+// The 'lang' parameter takes precedence over the Accept-Language header; in the latter case
+// the supported language with the highest weight is selected. English is the default language.
+func Negotiate(lang string, acceptLanguage string) (Language, error) {
+	if lang != "" {
+		return Parse(lang)
+	}
+
+	type weightedLanguage struct {
+		lang   Language
+		weight float64
+	}
+
+	candidates := make([]weightedLanguage, 0)
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		parsedLang, err := Parse(tag)
+		if err != nil {
+			continue
+		}
+
+		weight := 1.0
+		if qValue, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsedWeight, err := strconv.ParseFloat(qValue, 64); err == nil {
+				weight = parsedWeight
+			}
+		}
+
+		// A zero weight marks a language as not acceptable
+		if weight <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, weightedLanguage{parsedLang, weight})
+	}
+
+	if len(candidates) == 0 {
+		return English, nil
+	}
+
+	// Stable sort preserves the order of languages with the same weight
+	slices.SortStableFunc(candidates, func(a, b weightedLanguage) int {
+		switch {
+		case a.weight > b.weight:
+			return -1
+		case a.weight < b.weight:
+			return 1
+		}
+
+		return 0
+	})
+
+	return candidates[0].lang, nil
+}
+
+// Formats a date like time.Format, replacing English weekday and month names
+// (i.e. the 'Monday' and 'January' elements of the layout) with the localized ones
+func Format(date time.Time, layout string, lang Language) string {
+	if lang == English || lang == "" {
+		return date.Format(layout)
+	}
+
+	// Replace weekday and month elements with placeholders
+	// that are ignored by time.Format
+	layout = strings.ReplaceAll(layout, "Monday", "\x01")
+	layout = strings.ReplaceAll(layout, "January", "\x02")
+
+	cat := lang.catalog()
+	formatted := date.Format(layout)
+	formatted = strings.ReplaceAll(formatted, "\x01", cat.weekdays[date.Weekday()])
+	formatted = strings.ReplaceAll(formatted, "\x02", cat.months[date.Month()-1])
+
+	return formatted
+}
+
+// Returns the layout of the time of the day(e.g. '3:04 PM' or '15:04')
+func TimeLayout(lang Language) string {
+	return lang.catalog().timeLayout
+}
+
+// Translates an OpenWeatherMap weather condition(e.g. 'Clouds')
+func Condition(condition string, lang Language) string {
+	if translation, found := lang.catalog().conditions[condition]; found {
+		return translation
+	}
+
+	return condition
+}
+
+// Translates a moon phase(e.g. 'Full Moon')
+func MoonPhase(phase string, lang Language) string {
+	if translation, found := lang.catalog().moonPhases[phase]; found {
+		return translation
+	}
+
+	return phase
+}
@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Lang           string
+		AcceptLanguage string
+		Expected       Language
+	}{
+		{"Default language", "", "", English},
+		{"Parameter", "it", "de", Italian},
+		{"Region subtag", "fr-CH", "", French},
+		{"Header", "", "es-ES,es;q=0.9", Spanish},
+		{"Header weights", "", "ja;q=0.9,de;q=0.4,it;q=0.8", Italian},
+		{"Unsupported header", "", "ja,zh", English},
+		{"Not acceptable", "", "it;q=0", English},
+		{"Not acceptable fallback", "", "it;q=0,de;q=0.5", German},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := Negotiate(test.Lang, test.AcceptLanguage)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+
+	if _, err := Negotiate("xx", ""); err == nil {
+		t.Errorf("Expected an error for an unsupported language")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2025, time.August, 29, 20, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Layout   string
+		Lang     Language
+		Expected string
+	}{
+		{"English", "Monday, 2006/01/02 " + TimeLayout(English), English, "Friday, 2025/08/29 8:05 PM"},
+		{"Italian", "Monday, 2006/01/02 " + TimeLayout(Italian), Italian, "Venerdì, 2025/08/29 20:05"},
+		{"German month", "2 January 2006", German, "29 August 2025"},
+		{"French month", "Monday 2 January", French, "Vendredi 29 Août"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := Format(date, test.Layout, test.Lang)
+
+			if got != test.Expected {
+				t.Errorf("Got %s, wanted %s", got, test.Expected)
+			}
+		})
+	}
+}
@@ -6,13 +6,23 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
+	_ "time/tzdata" // Embed the timezone database
 
 	"github.com/ceticamarco/zephyr/cache"
 	"github.com/ceticamarco/zephyr/controller"
+	"github.com/ceticamarco/zephyr/geonames"
+	"github.com/ceticamarco/zephyr/model"
 	"github.com/ceticamarco/zephyr/types"
+	"github.com/ceticamarco/zephyr/web"
 )
 
 func main() {
+	// Run the import/export subcommands, if requested
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	// Retrieve listening port, API token and cache time-to-live from environment variables
 	var (
 		host   = os.Getenv("ZEPHYR_ADDR")
@@ -25,37 +35,193 @@ func main() {
 		log.Fatalf("Environment variables not set")
 	}
 
-	// Initialize cache, statDB and vars
+	// Retrieve the (optional) admin token. Admin endpoints are disabled when not set
+	adminToken := os.Getenv("ZEPHYR_ADMIN_TOKEN")
+
+	// Retrieve the (optional) rapid pressure fall threshold, expressed in hPa/3h
+	pressureDrop := 3.0
+	if val := os.Getenv("ZEPHYR_PRESSURE_DROP"); val != "" {
+		parsedDrop, err := strconv.ParseFloat(val, 64)
+		if err != nil || parsedDrop <= 0 {
+			log.Fatalf("Invalid value for ZEPHYR_PRESSURE_DROP")
+		}
+		pressureDrop = parsedDrop
+	}
+
+	// Pressure readings are collected once per cache time-to-live, hence
+	// the pressure history cannot hold two readings with longer TTLs
+	if time.Duration(ttl)*time.Hour >= cache.PressureRetention {
+		log.Printf("ZEPHYR_CACHE_TTL is too long to compute the pressure tendency(at most %d hours)",
+			int(cache.PressureRetention.Hours())-1)
+	}
+
+	// Load the (optional) GeoNames dump(e.g. 'cities15000.txt'), which seeds
+	// the suggestions and backs the offline geocoder. Without it, only
+	// the resolved locations are suggested
+	var index *geonames.Index
+	suggestions := &geonames.Index{}
+	if path := os.Getenv("ZEPHYR_GEONAMES_PATH"); path != "" {
+		loadedIndex, err := geonames.LoadFile(path)
+		if err != nil {
+			log.Fatalf("Cannot load the GeoNames dump: %v", err)
+		}
+		index, suggestions = loadedIndex, loadedIndex
+	}
+
+	// Select the geocoder, OpenWeatherMap is used when nil
+	var geocoder types.Geocoder
+	switch os.Getenv("ZEPHYR_GEOCODER") {
+	case "", "openweathermap": // Default geocoder
+	case "geonames":
+		if index == nil {
+			log.Fatalf("ZEPHYR_GEOCODER requires ZEPHYR_GEONAMES_PATH")
+		}
+		geocoder = index
+	default:
+		log.Fatalf("Invalid value for ZEPHYR_GEOCODER")
+	}
+
+	// Load the (optional) named locations(e.g. 'home'). Aliases are kept
+	// in memory only when no file is specified
+	aliases := model.NewAliasStore()
+	if path := os.Getenv("ZEPHYR_ALIASES"); path != "" {
+		loadedAliases, err := model.LoadAliases(path)
+		if err != nil {
+			log.Fatalf("Cannot load the aliases: %v", err)
+		}
+		aliases = loadedAliases
+	}
+
+	// Initialize cache, statDB, pressure history and vars
 	masterCache := cache.InitMasterCache()
 	statCache := cache.InitStatCache()
+	pressureCache := cache.InitPressureCache()
 	vars := types.Variables{
-		Token:      token,
-		TimeToLive: int8(ttl),
+		Token:        token,
+		AdminToken:   adminToken,
+		TimeToLive:   int8(ttl),
+		PressureDrop: pressureDrop,
+		Geocoder:     geocoder,
+		Suggestions:  suggestions,
+		Aliases:      aliases,
 	}
 
 	// API endpoints
 	http.HandleFunc("/weather/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetWeather(res, req, &masterCache.WeatherCache, statCache, &vars)
+		controller.GetWeather(res, req, masterCache, statCache, pressureCache, &vars)
+	})
+
+	http.HandleFunc("/weather", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWeatherBatch(res, req, &masterCache.WeatherCache, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/batch", func(res http.ResponseWriter, req *http.Request) {
+		controller.PostBatch(res, req, &masterCache.WeatherCache, &masterCache.ResolveCache, statCache, &vars)
 	})
 
 	http.HandleFunc("/metrics/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetMetrics(res, req, &masterCache.MetricsCache, &vars)
+		controller.GetMetrics(res, req, &masterCache.MetricsCache, &masterCache.ResolveCache, pressureCache, &vars)
 	})
 
 	http.HandleFunc("/wind/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetWind(res, req, &masterCache.WindCache, &vars)
+		controller.GetWind(res, req, &masterCache.WindCache, &masterCache.ResolveCache, &vars)
 	})
 
 	http.HandleFunc("/forecast/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetForecast(res, req, &masterCache.DailyForecastCache, &masterCache.HourlyForecastCache, &vars)
+		controller.GetForecast(res, req, &masterCache.DailyForecastCache, &masterCache.HourlyForecastCache, &masterCache.ResolveCache, &vars)
+	})
+
+	http.HandleFunc("/summary/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetSummary(res, req, masterCache, statCache, pressureCache, &vars)
+	})
+
+	http.HandleFunc("/widget/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWidget(res, req, masterCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/eink/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetEinkDisplay(res, req, masterCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/geo/search", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetLocations(res, req, &masterCache.GeoCache, &vars)
+	})
+
+	http.HandleFunc("/geo/autocomplete", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetSuggestions(res, req, &vars)
+	})
+
+	http.HandleFunc("/geo/reverse", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetPlaces(res, req, &masterCache.ReverseGeoCache, &vars)
+	})
+
+	http.HandleFunc("/nearby/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetNearby(res, req, index, masterCache, &vars)
 	})
 
 	http.HandleFunc("/moon", func(res http.ResponseWriter, req *http.Request) {
 		controller.GetMoon(res, req, &masterCache.MoonCache, &vars)
 	})
 
+	http.HandleFunc("/stats/compare", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetStatisticsComparison(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
 	http.HandleFunc("/stats/", func(res http.ResponseWriter, req *http.Request) {
-		controller.GetStatistics(res, req, statCache)
+		controller.GetStatistics(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	// Version 2 API endpoints
+	http.HandleFunc("/v2/weather/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWeatherV2(res, req, &masterCache.WeatherCache, &masterCache.ResolveCache, &masterCache.ReverseGeoCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/v2/metrics/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMetricsV2(res, req, &masterCache.MetricsCache, &masterCache.ResolveCache, pressureCache, &vars)
+	})
+
+	http.HandleFunc("/v2/wind/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWindV2(res, req, &masterCache.WindCache, &masterCache.ResolveCache, &vars)
+	})
+
+	http.HandleFunc("/v2/forecast/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetForecastV2(res, req, &masterCache.DailyForecastCache, &masterCache.HourlyForecastCache, &masterCache.ResolveCache, &vars)
+	})
+
+	http.HandleFunc("/v2/moon", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetMoonV2(res, req, &masterCache.MoonCache, &vars)
+	})
+
+	http.HandleFunc("/v2/stats/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetStatisticsV2(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	// Web interface
+	http.Handle("/static/", http.StripPrefix("/static/", web.Static()))
+
+	http.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		controller.GetWebPage(res, req, masterCache, statCache, pressureCache, &vars)
+	})
+
+	// Admin endpoints
+	http.HandleFunc("/admin/stats/export", func(res http.ResponseWriter, req *http.Request) {
+		controller.ExportStatistics(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/admin/stats/import", func(res http.ResponseWriter, req *http.Request) {
+		controller.ImportStatistics(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/admin/stats/migrate", func(res http.ResponseWriter, req *http.Request) {
+		controller.MigrateStatistics(res, req, &masterCache.ResolveCache, statCache, &vars)
+	})
+
+	http.HandleFunc("/admin/aliases", func(res http.ResponseWriter, req *http.Request) {
+		controller.ManageAliases(res, req, &vars)
+	})
+
+	http.HandleFunc("/admin/aliases/", func(res http.ResponseWriter, req *http.Request) {
+		controller.ManageAliases(res, req, &vars)
 	})
 
 	listenAddr := fmt.Sprintf("%s:%s", host, port)
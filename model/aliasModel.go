@@ -0,0 +1,195 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// AliasStore, representing the named locations along with
+// the (optional) file they are persisted to
+type AliasStore struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]types.Alias
+}
+
+// Returns an empty store, kept in memory only
+func NewAliasStore() *AliasStore {
+	return &AliasStore{entries: make(map[string]types.Alias)}
+}
+
+// Aliases are case insensitive
+func aliasKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Loads the aliases from a JSON file, which is created on the first change
+// if it does not exist. Changes made through Set and Delete are persisted to it
+func LoadAliases(path string) (*AliasStore, error) {
+	store := NewAliasStore()
+	store.path = path
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var file types.Aliases
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	for _, alias := range file.Aliases {
+		if err := validateAlias(&alias); err != nil {
+			return nil, err
+		}
+		store.entries[aliasKey(alias.Name)] = alias
+	}
+
+	return store, nil
+}
+
+// Checks that an alias maps either to valid coordinates or to a query
+func validateAlias(alias *types.Alias) error {
+	alias.Name = strings.TrimSpace(alias.Name)
+	alias.Query = strings.TrimSpace(alias.Query)
+
+	if alias.Name == "" || strings.ContainsAny(alias.Name, ",/") {
+		return errors.New("alias name must not be empty nor contain ',' or '/'")
+	}
+
+	hasCoordinates := alias.Lat != nil || alias.Lon != nil
+	if hasCoordinates == (alias.Query != "") {
+		return errors.New("alias must specify either coordinates or a query")
+	}
+
+	if hasCoordinates {
+		if alias.Lat == nil || *alias.Lat < -90 || *alias.Lat > 90 {
+			return errors.New("latitude must be between -90 and 90")
+		}
+
+		if alias.Lon == nil || *alias.Lon < -180 || *alias.Lon > 180 {
+			return errors.New("longitude must be between -180 and 180")
+		}
+	}
+
+	return nil
+}
+
+// Writes the aliases to their file, if any. The file is replaced
+// atomically, so that a failed write does not lose the previous aliases
+func (store *AliasStore) persist() error {
+	if store.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(types.Aliases{Aliases: store.list()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), ".aliases-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), store.path)
+}
+
+// Returns the aliases sorted by name
+func (store *AliasStore) list() []types.Alias {
+	list := make([]types.Alias, 0, len(store.entries))
+	for _, alias := range store.entries {
+		list = append(list, alias)
+	}
+
+	slices.SortFunc(list, func(x, y types.Alias) int {
+		return strings.Compare(aliasKey(x.Name), aliasKey(y.Name))
+	})
+
+	return list
+}
+
+// Retrieves the alias of a name, if any
+func (store *AliasStore) Get(name string) (types.Alias, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	alias, found := store.entries[aliasKey(name)]
+
+	return alias, found
+}
+
+func (store *AliasStore) List() types.Aliases {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return types.Aliases{Aliases: store.list()}
+}
+
+// Adds or replaces an alias
+func (store *AliasStore) Set(alias types.Alias) error {
+	if err := validateAlias(&alias); err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	previous, found := store.entries[aliasKey(alias.Name)]
+	store.entries[aliasKey(alias.Name)] = alias
+
+	// Restore the previous state if the aliases cannot be persisted
+	if err := store.persist(); err != nil {
+		if found {
+			store.entries[aliasKey(alias.Name)] = previous
+		} else {
+			delete(store.entries, aliasKey(alias.Name))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Removes an alias, returns false if it does not exist
+func (store *AliasStore) Delete(name string) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	previous, found := store.entries[aliasKey(name)]
+	if !found {
+		return false, nil
+	}
+
+	delete(store.entries, aliasKey(name))
+
+	if err := store.persist(); err != nil {
+		store.entries[aliasKey(name)] = previous
+		return false, err
+	}
+
+	return true, nil
+}
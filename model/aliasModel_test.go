@@ -0,0 +1,75 @@
+package model
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestValidateAlias(t *testing.T) {
+	coordinate := func(val float64) *float64 { return &val }
+
+	tests := []struct {
+		Name     string
+		Alias    types.Alias
+		Expected bool
+	}{
+		{"Coordinates", types.Alias{Name: "home", Lat: coordinate(45.46), Lon: coordinate(9.19)}, true},
+		{"Query", types.Alias{Name: "office", Query: "Milan,IT"}, true},
+		{"Empty name", types.Alias{Name: " ", Query: "Milan"}, false},
+		{"Comma in the name", types.Alias{Name: "home,IT", Query: "Milan"}, false},
+		{"Neither coordinates nor query", types.Alias{Name: "home"}, false},
+		{"Both coordinates and query", types.Alias{Name: "home", Lat: coordinate(45.46), Lon: coordinate(9.19), Query: "Milan"}, false},
+		{"Missing longitude", types.Alias{Name: "home", Lat: coordinate(45.46)}, false},
+		{"Latitude out of range", types.Alias{Name: "home", Lat: coordinate(91), Lon: coordinate(9.19)}, false},
+		{"Longitude out of range", types.Alias{Name: "home", Lat: coordinate(45.46), Lon: coordinate(-181)}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := validateAlias(&test.Alias) == nil; got != test.Expected {
+				t.Errorf("Got %t, wanted %t", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestAliasStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+
+	store, err := LoadAliases(path)
+	if err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	if err := store.Set(types.Alias{Name: " Office ", Query: "Milan,IT"}); err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	if err := store.Set(types.Alias{Name: "home"}); err == nil {
+		t.Errorf("Got no error, wanted an invalid alias")
+	}
+
+	// Aliases are persisted and case insensitive
+	reloaded, err := LoadAliases(path)
+	if err != nil {
+		t.Fatalf("Got %s, wanted no error", err)
+	}
+
+	if alias, found := reloaded.Get("OFFICE"); !found || alias.Name != "Office" || alias.Query != "Milan,IT" {
+		t.Errorf("Got %v, wanted the 'Office' alias", alias)
+	}
+
+	if got := len(reloaded.List().Aliases); got != 1 {
+		t.Errorf("Got %d aliases, wanted 1", got)
+	}
+
+	if deleted, err := reloaded.Delete("office"); !deleted || err != nil {
+		t.Errorf("Got (%t, %v), wanted (true, <nil>)", deleted, err)
+	}
+
+	if deleted, _ := reloaded.Delete("office"); deleted {
+		t.Errorf("Got %t, wanted false", deleted)
+	}
+}
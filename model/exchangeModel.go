@@ -0,0 +1,153 @@
+package model
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+type ExchangeFormat string
+
+const (
+	CSV    ExchangeFormat = "csv"
+	NDJSON ExchangeFormat = "ndjson"
+)
+
+var csvHeader = []string{"city", "date", "temperature"}
+
+func ParseExchangeFormat(format string) (ExchangeFormat, error) {
+	switch ExchangeFormat(strings.ToLower(format)) {
+	case "", CSV:
+		return CSV, nil
+	case NDJSON:
+		return NDJSON, nil
+	}
+
+	return "", errors.New("invalid format, use either 'csv' or 'ndjson'")
+}
+
+func validateRecord(record types.StatRecord) error {
+	if strings.TrimSpace(record.City) == "" {
+		return errors.New("missing city name")
+	}
+
+	if _, err := time.Parse("2006-01-02", record.Date); err != nil {
+		return fmt.Errorf("invalid date '%s', expected YYYY-MM-DD", record.Date)
+	}
+
+	if math.IsNaN(record.Temperature) || math.IsInf(record.Temperature, 0) {
+		return errors.New("invalid temperature")
+	}
+
+	return nil
+}
+
+func EncodeStatRecords(w io.Writer, records []types.StatRecord, format ExchangeFormat) error {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			row := []string{
+				record.City,
+				record.Date,
+				strconv.FormatFloat(record.Temperature, 'f', -1, 64),
+			}
+
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return errors.New("unsupported format")
+}
+
+func DecodeStatRecords(r io.Reader, format ExchangeFormat) ([]types.StatRecord, error) {
+	records := make([]types.StatRecord, 0)
+
+	switch format {
+	case CSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = len(csvHeader)
+		reader.TrimLeadingSpace = true
+
+		for line := 1; ; line++ {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			// Skip the (optional) header
+			if line == 1 && strings.EqualFold(row[0], csvHeader[0]) {
+				continue
+			}
+
+			temp, err := strconv.ParseFloat(row[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid temperature '%s'", line, row[2])
+			}
+
+			record := types.StatRecord{City: row[0], Date: row[1], Temperature: temp}
+			if err := validateRecord(record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			records = append(records, record)
+		}
+	case NDJSON:
+		scanner := bufio.NewScanner(r)
+		for line := 1; scanner.Scan(); line++ {
+			// Skip blank lines
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+
+			var record types.StatRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			if err := validateRecord(record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			records = append(records, record)
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported format")
+	}
+
+	return records, nil
+}
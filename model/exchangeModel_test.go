@@ -0,0 +1,78 @@
+package model
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestStatRecordsRoundTrip(t *testing.T) {
+	records := []types.StatRecord{
+		{City: "45.46,9.19", Date: "2025-05-28", Temperature: 22.4},
+		{City: "45.46,9.19", Date: "2025-05-29", Temperature: -3},
+		{City: "NEW+YORK", Date: "2025-05-28", Temperature: 18.25},
+	}
+
+	for _, format := range []ExchangeFormat{CSV, NDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeStatRecords(&buf, records, format); err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			got, err := DecodeStatRecords(&buf, format)
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if !slices.Equal(got, records) {
+				t.Errorf("Got %v, wanted %v", got, records)
+			}
+		})
+	}
+}
+
+func TestDecodeStatRecords(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Format ExchangeFormat
+		Input  string
+		Count  int
+		Error  bool
+	}{
+		{"CSV without header", CSV, "MILAN,2025-05-28,22.4\n", 1, false},
+		{"CSV with header", CSV, "city,date,temperature\n\"45.46,9.19\",2025-05-28,22.4\n", 1, false},
+		{"CSV invalid date", CSV, "MILAN,28/05/2025,22.4\n", 0, true},
+		{"CSV missing column", CSV, "MILAN,2025-05-28\n", 0, true},
+		{"CSV non-numeric temperature", CSV, "MILAN,2025-05-28,warm\n", 0, true},
+		{"CSV missing city", CSV, " ,2025-05-28,22.4\n", 0, true},
+		{"NDJSON blank lines", NDJSON, "{\"city\":\"MILAN\",\"date\":\"2025-05-28\",\"temperature\":22.4}\n\n", 1, false},
+		{"NDJSON invalid date", NDJSON, "{\"city\":\"MILAN\",\"date\":\"2025-13-01\",\"temperature\":22.4}\n", 0, true},
+		{"NDJSON missing city", NDJSON, "{\"date\":\"2025-05-28\",\"temperature\":22.4}\n", 0, true},
+		{"NDJSON non-numeric temperature", NDJSON, "{\"city\":\"MILAN\",\"date\":\"2025-05-28\",\"temperature\":\"warm\"}\n", 0, true},
+		{"Unsupported format", ExchangeFormat("xml"), "", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := DecodeStatRecords(strings.NewReader(test.Input), test.Format)
+			if test.Error {
+				if err == nil {
+					t.Errorf("Got %v, wanted an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			if len(got) != test.Count {
+				t.Errorf("Got %d records, wanted %d", len(got), test.Count)
+			}
+		})
+	}
+}
@@ -40,7 +40,9 @@ type dailyRes struct {
 }
 
 type dailyForecastRes struct {
-	Daily []dailyRes `json:"daily"`
+	Timezone       string     `json:"timezone"`
+	TimezoneOffset int        `json:"timezone_offset"`
+	Daily          []dailyRes `json:"daily"`
 }
 
 // Structure representing the hourly forecast
@@ -58,7 +60,9 @@ type hourlyRes struct {
 }
 
 type hourlyForecastRes struct {
-	Hourly []hourlyRes `json:"hourly"`
+	Timezone       string      `json:"timezone"`
+	TimezoneOffset int         `json:"timezone_offset"`
+	Hourly         []hourlyRes `json:"hourly"`
 }
 
 func getForecastEntity[T types.DailyForecastEntity | types.HourlyForecastEntity, K dailyRes | hourlyRes](forecast K) T {
@@ -103,7 +107,7 @@ func getForecastEntity[T types.DailyForecastEntity | types.HourlyForecastEntity,
 			RainProb: strconv.FormatInt(rainProb, 10) + "%",
 		}).(T)
 	case hourlyRes:
-		// Format UNIX timestamp as 'YYYY-MM-DD'
+		// Format UNIX timestamp as 'HH:MM'
 		utcTime := time.Unix(int64(fc.Timestamp), 0)
 		weatherTime := types.ZephyrTime{Time: utcTime.UTC()}
 
@@ -185,7 +189,11 @@ func GetForecast[T types.DailyForecast | types.HourlyForecast](city *types.City,
 		for _, val := range dailyRes.Daily[1:5] {
 			forecastEntities = append(forecastEntities, getForecastEntity[types.DailyForecastEntity](val))
 		}
-		forecast = any(types.DailyForecast{Forecast: forecastEntities}).(T)
+		forecast = any(types.DailyForecast{
+			Timezone:       dailyRes.Timezone,
+			TimezoneOffset: dailyRes.TimezoneOffset,
+			Forecast:       forecastEntities,
+		}).(T)
 
 	case HOURLY:
 		params.Set("exclude", "current,minutely,daily,alerts")
@@ -209,7 +217,11 @@ func GetForecast[T types.DailyForecast | types.HourlyForecast](city *types.City,
 		for _, val := range hourlyRes.Hourly[:9] {
 			forecastEntries = append(forecastEntries, getForecastEntity[types.HourlyForecastEntity](val))
 		}
-		forecast = any(types.HourlyForecast{Forecast: forecastEntries}).(T)
+		forecast = any(types.HourlyForecast{
+			Timezone:       hourlyRes.Timezone,
+			TimezoneOffset: hourlyRes.TimezoneOffset,
+			Forecast:       forecastEntries,
+		}).(T)
 	}
 
 	return any(forecast).(T), nil
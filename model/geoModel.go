@@ -3,33 +3,142 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func GetCoordinates(cityName string, apiKey string) (types.City, error) {
+// Maximum number of candidates returned by OpenWeatherMap
+const MaxLocations = 5
+
+// Checks whether a query is a postal code(e.g. '20121,IT'), that is,
+// whether the first part of the query contains a digit
+func isPostalCode(query string) bool {
+	code, _, _ := strings.Cut(query, ",")
+
+	return strings.ContainsFunc(code, unicode.IsDigit)
+}
+
+func getPostalCode(query string, apiKey string) ([]types.City, error) {
+	url, err := url.Parse(ZIP_URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the spaces around the country code(e.g. '20121, IT')
+	code, country, found := strings.Cut(query, ",")
+	zip := strings.TrimSpace(code)
+	if found {
+		zip += "," + strings.TrimSpace(country)
+	}
+
+	params := url.Query()
+	params.Set("zip", zip)
+	params.Set("appid", apiKey)
+
+	url.RawQuery = params.Encode()
+
+	res, err := http.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// Unknown postal codes are reported with a 404 status code
+	if res.StatusCode == http.StatusNotFound {
+		return []types.City{}, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("postal code lookup failed with status %d", res.StatusCode)
+	}
+
+	var city types.City
+	if err := json.NewDecoder(res.Body).Decode(&city); err != nil {
+		return nil, err
+	}
+
+	if city.Name == "" {
+		return nil, errors.New("postal code lookup returned an unnamed location")
+	}
+
+	return []types.City{city}, nil
+}
+
+// Retrieves the candidates of a query, that is, either a location name in the 'city,state,country'
+// format or a postal code in the 'zip,country' format. A nil geocoder selects OpenWeatherMap
+func SearchLocations(query string, limit int, geocoder types.Geocoder, apiKey string) ([]types.City, error) {
+	if geocoder != nil {
+		return geocoder.Search(query, min(limit, MaxLocations)), nil
+	}
+
+	if isPostalCode(query) {
+		return getPostalCode(query, apiKey)
+	}
+
 	url, err := url.Parse(GEO_URL)
 	if err != nil {
-		return types.City{}, err
+		return nil, err
 	}
 
 	params := url.Query()
-	params.Set("q", cityName)
-	params.Set("limit", "1")
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(min(limit, MaxLocations)))
 	params.Set("appid", apiKey)
 
 	url.RawQuery = params.Encode()
 
 	res, err := http.Get(url.String())
 	if err != nil {
-		return types.City{}, err
+		return nil, err
 	}
 	defer res.Body.Close()
 
 	var geoArr []types.City
 	if err := json.NewDecoder(res.Body).Decode(&geoArr); err != nil {
+		return nil, err
+	}
+
+	return geoArr, nil
+}
+
+// Retrieves the nearest named places of a set of coordinates
+func GetPlaces(lat, lon float64, limit int, apiKey string) ([]types.City, error) {
+	url, err := url.Parse(REV_URL)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Query()
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+	params.Set("limit", strconv.Itoa(min(limit, MaxLocations)))
+	params.Set("appid", apiKey)
+
+	url.RawQuery = params.Encode()
+
+	res, err := http.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var geoArr []types.City
+	if err := json.NewDecoder(res.Body).Decode(&geoArr); err != nil {
+		return nil, err
+	}
+
+	return geoArr, nil
+}
+
+func GetCoordinates(cityName string, geocoder types.Geocoder, apiKey string) (types.City, error) {
+	geoArr, err := SearchLocations(cityName, 1, geocoder, apiKey)
+	if err != nil {
 		return types.City{}, err
 	}
 
@@ -38,8 +147,10 @@ func GetCoordinates(cityName string, apiKey string) (types.City, error) {
 	}
 
 	return types.City{
-		Name: geoArr[0].Name,
-		Lat:  geoArr[0].Lat,
-		Lon:  geoArr[0].Lon,
+		Name:    geoArr[0].Name,
+		State:   geoArr[0].State,
+		Country: geoArr[0].Country,
+		Lat:     geoArr[0].Lat,
+		Lon:     geoArr[0].Lon,
 	}, nil
 }
@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestIsPostalCode(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Query    string
+		Expected bool
+	}{
+		{"City", "Milan", false},
+		{"City, state and country", "Springfield,IL,US", false},
+		{"Postal code", "20121,IT", true},
+		{"Alphanumeric postal code", "SW1A 1AA, GB", true},
+		{"Digits in the country", "Milan,1", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := isPostalCode(test.Query); got != test.Expected {
+				t.Errorf("Got %t, wanted %t", got, test.Expected)
+			}
+		})
+	}
+}
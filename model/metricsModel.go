@@ -6,7 +6,10 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
+	"github.com/ceticamarco/zephyr/cache"
+	"github.com/ceticamarco/zephyr/statistics"
 	"github.com/ceticamarco/zephyr/types"
 )
 
@@ -55,3 +58,33 @@ func GetMetrics(city *types.City, apiKey string) (types.Metrics, error) {
 		Visibility: strconv.FormatFloat((metricRes.Current.Visibility / 1000), 'f', -1, 64),
 	}, nil
 }
+
+func GetPressureTendency(cityName string, pressureCache *cache.PressureCache, dropThreshold float64, ttl int8) *types.PressureTendency {
+	// A pressure change below 1 hPa in 3 hours is considered steady
+	const steadyThreshold = 1.0
+
+	// Readings are collected at most once per cache time-to-live, hence
+	// longer TTLs need an older reference reading
+	maxAge := max(6*time.Hour, 2*time.Duration(ttl)*time.Hour)
+
+	rate, found := statistics.PressureTendency(pressureCache.GetCityReadings(cityName), maxAge)
+	if !found {
+		return nil
+	}
+
+	var trend string
+	switch {
+	case rate >= steadyThreshold:
+		trend = "rising"
+	case rate <= -steadyThreshold:
+		trend = "falling"
+	default:
+		trend = "steady"
+	}
+
+	return &types.PressureTendency{
+		Trend:     trend,
+		Rate:      strconv.FormatFloat(rate, 'f', -1, 64),
+		RapidFall: rate <= -dropThreshold,
+	}
+}
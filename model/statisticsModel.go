@@ -2,15 +2,29 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"slices"
 	"strconv"
+	"time"
 
 	"github.com/ceticamarco/zephyr/cache"
 	"github.com/ceticamarco/zephyr/statistics"
 	"github.com/ceticamarco/zephyr/types"
 )
 
-func GetStatistics(cityName string, statCache *cache.StatCache) (types.StatResult, error) {
+func getConfidenceInterval(lower, upper float64, found bool, level float64) *types.ConfidenceInterval {
+	if !found {
+		return nil
+	}
+
+	return &types.ConfidenceInterval{
+		Level: strconv.FormatFloat(level*100, 'f', -1, 64),
+		Lower: strconv.FormatFloat(lower, 'f', -1, 64),
+		Upper: strconv.FormatFloat(upper, 'f', -1, 64),
+	}
+}
+
+func GetStatistics(cityName string, confidence float64, statCache *cache.StatCache) (types.StatResult, error) {
 	// Check whether there are sufficient and updated records for the given location
 	if statCache.IsKeyInvalid(cityName) {
 		return types.StatResult{}, errors.New("insufficient or outdated data to perform statistical analysis")
@@ -30,15 +44,162 @@ func GetStatistics(cityName string, statCache *cache.StatCache) (types.StatResul
 		anomalies = nil
 	}
 
+	// Compute confidence intervals of mean and median
+	meanLower, meanUpper, meanFound := statistics.MeanConfidenceInterval(temps, confidence)
+	medianLower, medianUpper, medianFound := statistics.MedianConfidenceInterval(temps, confidence)
+
 	// Compute statistics
 	return types.StatResult{
-		Min:     strconv.FormatFloat(slices.Min(temps), 'f', -1, 64),
-		Max:     strconv.FormatFloat(slices.Max(temps), 'f', -1, 64),
-		Count:   len(stats),
-		Mean:    strconv.FormatFloat(statistics.Mean(temps), 'f', -1, 64),
-		StdDev:  strconv.FormatFloat(statistics.StdDev(temps), 'f', -1, 64),
-		Median:  strconv.FormatFloat(statistics.Median(temps), 'f', -1, 64),
-		Mode:    strconv.FormatFloat(statistics.Mode(temps), 'f', -1, 64),
-		Anomaly: &anomalies,
+		Min:      strconv.FormatFloat(slices.Min(temps), 'f', -1, 64),
+		Max:      strconv.FormatFloat(slices.Max(temps), 'f', -1, 64),
+		Count:    len(stats),
+		Mean:     strconv.FormatFloat(statistics.Mean(temps), 'f', -1, 64),
+		MeanCI:   getConfidenceInterval(meanLower, meanUpper, meanFound, confidence),
+		StdDev:   strconv.FormatFloat(statistics.StdDev(temps), 'f', -1, 64),
+		Median:   strconv.FormatFloat(statistics.Median(temps), 'f', -1, 64),
+		MedianCI: getConfidenceInterval(medianLower, medianUpper, medianFound, confidence),
+		Mode:     strconv.FormatFloat(statistics.Mode(temps), 'f', -1, 64),
+		Anomaly:  &anomalies,
 	}, nil
 }
+
+func GetGroupedStatistics(cityName string, groupBy string, statCache *cache.StatCache) (types.GroupedStatResult, error) {
+	// Retrieve the period label of a given date
+	var getPeriod func(date time.Time) string
+	switch groupBy {
+	case "week":
+		getPeriod = func(date time.Time) string {
+			year, week := date.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+	case "month":
+		getPeriod = func(date time.Time) string {
+			return date.Format("2006-01")
+		}
+	default:
+		return types.GroupedStatResult{}, errors.New("invalid grouping, use either 'week' or 'month'")
+	}
+
+	// Check whether there are sufficient and updated records for the given location
+	if statCache.IsKeyInvalid(cityName) {
+		return types.GroupedStatResult{}, errors.New("insufficient or outdated data to perform statistical analysis")
+	}
+
+	// Extract records from the database and sort them chronologically
+	stats := statCache.GetCityStatistics(cityName)
+	slices.SortFunc(stats, func(a, b types.StatElement) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	// Group records by period. Since records are sorted,
+	// each period is a contiguous slice of records
+	periods := make([]types.StatPeriod, 0)
+	for start := 0; start < len(stats); {
+		period := getPeriod(stats[start].Date)
+
+		end := start
+		temps := make([]float64, 0)
+		for end < len(stats) && getPeriod(stats[end].Date) == period {
+			temps = append(temps, stats[end].Temperature)
+			end++
+		}
+
+		periods = append(periods, types.StatPeriod{
+			Period: period,
+			From:   types.ZephyrDate{Date: stats[start].Date},
+			To:     types.ZephyrDate{Date: stats[end-1].Date},
+			Count:  len(temps),
+			Min:    strconv.FormatFloat(slices.Min(temps), 'f', -1, 64),
+			Max:    strconv.FormatFloat(slices.Max(temps), 'f', -1, 64),
+			Mean:   strconv.FormatFloat(statistics.Mean(temps), 'f', -1, 64),
+			StdDev: strconv.FormatFloat(statistics.StdDev(temps), 'f', -1, 64),
+			Median: strconv.FormatFloat(statistics.Median(temps), 'f', -1, 64),
+		})
+
+		start = end
+	}
+
+	return types.GroupedStatResult{
+		GroupBy: groupBy,
+		Periods: periods,
+	}, nil
+}
+
+func compareCities(first, second []types.StatElement) types.StatPairResult {
+	// Align both series on the dates they have in common
+	secondTemps := make(map[time.Time]float64, len(second))
+	for _, stat := range second {
+		secondTemps[stat.Date] = stat.Temperature
+	}
+
+	alignedStats := make([]types.StatElement, 0)
+	for _, stat := range first {
+		if _, exists := secondTemps[stat.Date]; exists {
+			alignedStats = append(alignedStats, stat)
+		}
+	}
+
+	slices.SortFunc(alignedStats, func(a, b types.StatElement) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	var result types.StatPairResult
+	result.Overlap = len(alignedStats)
+	if result.Overlap == 0 {
+		return result
+	}
+
+	firstTemps := make([]float64, len(alignedStats))
+	alignedTemps := make([]float64, len(alignedStats))
+	for idx, stat := range alignedStats {
+		firstTemps[idx] = stat.Temperature
+		alignedTemps[idx] = secondTemps[stat.Date]
+	}
+
+	meanDiff := strconv.FormatFloat(statistics.Mean(firstTemps)-statistics.Mean(alignedTemps), 'f', -1, 64)
+	result.From = types.ZephyrDate{Date: alignedStats[0].Date}
+	result.To = types.ZephyrDate{Date: alignedStats[len(alignedStats)-1].Date}
+	result.MeanDiff = &meanDiff
+
+	if corr, found := statistics.Pearson(firstTemps, alignedTemps); found {
+		fmtCorr := strconv.FormatFloat(corr, 'f', 4, 64)
+		result.Correlation = &fmtCorr
+	}
+
+	return result
+}
+
+func GetStatisticsComparison(cityNames []string, cityKeys []string, confidence float64, statCache *cache.StatCache) (types.StatComparison, error) {
+	result := types.StatComparison{
+		Cities: make([]types.CityStatResult, 0, len(cityKeys)),
+		Pairs:  make([]types.StatPairResult, 0),
+	}
+
+	// Compute the summary of each location
+	cityStats := make([][]types.StatElement, len(cityKeys))
+	for idx, cityKey := range cityKeys {
+		stats, err := GetStatistics(cityKey, confidence, statCache)
+		if err != nil {
+			return types.StatComparison{}, fmt.Errorf("%s: %w", cityNames[idx], err)
+		}
+
+		result.Cities = append(result.Cities, types.CityStatResult{
+			City:       cityNames[idx],
+			Statistics: stats,
+		})
+		cityStats[idx] = statCache.GetCityStatistics(cityKey)
+	}
+
+	// Compare each pair of locations
+	for i := 0; i < len(cityKeys); i++ {
+		for j := i + 1; j < len(cityKeys); j++ {
+			pair := compareCities(cityStats[i], cityStats[j])
+			pair.First = cityNames[i]
+			pair.Second = cityNames[j]
+
+			result.Pairs = append(result.Pairs, pair)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/cache"
+)
+
+func TestGetGroupedStatistics(t *testing.T) {
+	// Records are valid only with at least two readings within the last two days
+	today := time.Now().UTC()
+	recent := []string{today.AddDate(0, 0, -1).Format("2006-01-02"), today.Format("2006-01-02")}
+
+	tests := []struct {
+		Name     string
+		GroupBy  string
+		Dates    []string
+		Expected []string
+	}{
+		{"ISO week across the year boundary", "week", []string{"2024-12-30", "2025-01-02", "2024-12-29"}, []string{"2024-W52", "2025-W01"}},
+		{"Month", "month", []string{"2024-12-30", "2024-12-01", "2025-01-02"}, []string{"2024-12", "2025-01"}},
+		{"Unsorted input", "month", []string{"2025-03-10", "2025-01-05", "2025-03-01", "2025-02-14"}, []string{"2025-01", "2025-02", "2025-03"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			statCache := cache.InitStatCache()
+			for idx, date := range append(test.Dates, recent...) {
+				statCache.AddStatistic("45.46,9.19", date, float64(idx))
+			}
+
+			got, err := GetGroupedStatistics("45.46,9.19", test.GroupBy, statCache)
+			if err != nil {
+				t.Fatalf("Got %s, wanted no error", err)
+			}
+
+			var periods []string
+			for _, period := range got.Periods {
+				periods = append(periods, period.Period)
+			}
+
+			// The recent records are grouped after the tested ones
+			if len(periods) < len(test.Expected) || !slices.Equal(periods[:len(test.Expected)], test.Expected) {
+				t.Errorf("Got %v, wanted %v", periods, test.Expected)
+			}
+		})
+	}
+
+	t.Run("Records of a period", func(t *testing.T) {
+		statCache := cache.InitStatCache()
+		for date, temp := range map[string]float64{"2024-12-30": 10, "2025-01-05": 14, "2025-01-06": 20} {
+			statCache.AddStatistic("45.46,9.19", date, temp)
+		}
+		for _, date := range recent {
+			statCache.AddStatistic("45.46,9.19", date, 0)
+		}
+
+		got, err := GetGroupedStatistics("45.46,9.19", "week", statCache)
+		if err != nil {
+			t.Fatalf("Got %s, wanted no error", err)
+		}
+
+		first := got.Periods[0]
+		if first.Period != "2025-W01" || first.Count != 2 || first.Min != "10" || first.Max != "14" || first.Mean != "12" {
+			t.Errorf("Got %+v, wanted 2 records of 2025-W01", first)
+		}
+
+		if from, to := first.From.Date.Format("2006-01-02"), first.To.Date.Format("2006-01-02"); from != "2024-12-30" || to != "2025-01-05" {
+			t.Errorf("Got %s - %s, wanted 2024-12-30 - 2025-01-05", from, to)
+		}
+	})
+
+	t.Run("Invalid grouping", func(t *testing.T) {
+		if _, err := GetGroupedStatistics("45.46,9.19", "year", cache.InitStatCache()); err == nil {
+			t.Errorf("Got no error, wanted an invalid grouping")
+		}
+	})
+
+	t.Run("Insufficient data", func(t *testing.T) {
+		if _, err := GetGroupedStatistics("45.46,9.19", "week", cache.InitStatCache()); err == nil {
+			t.Errorf("Got no error, wanted insufficient data")
+		}
+	})
+}
@@ -2,5 +2,7 @@ package model
 
 const (
 	GEO_URL = "https://api.openweathermap.org/geo/1.0/direct"
+	ZIP_URL = "https://api.openweathermap.org/geo/1.0/zip"
+	REV_URL = "https://api.openweathermap.org/geo/1.0/reverse"
 	WTR_URL = "https://api.openweathermap.org/data/3.0/onecall"
 )
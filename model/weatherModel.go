@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ceticamarco/zephyr/i18n"
 	"github.com/ceticamarco/zephyr/types"
 )
 
@@ -42,7 +43,7 @@ func GetEmoji(condition string, isNight bool) string {
 	return "❓"
 }
 
-func GetWeather(city *types.City, apiKey string) (types.Weather, float64, error) {
+func GetWeather(city *types.City, apiKey string, lang i18n.Language) (types.Weather, float64, error) {
 	url, err := url.Parse(WTR_URL)
 	if err != nil {
 		return types.Weather{}, 0, err
@@ -54,6 +55,7 @@ func GetWeather(city *types.City, apiKey string) (types.Weather, float64, error)
 	params.Set("appid", apiKey)
 	params.Set("units", "metric")
 	params.Set("exclude", "minutely,hourly")
+	params.Set("lang", string(lang))
 
 	url.RawQuery = params.Encode()
 
@@ -65,7 +67,9 @@ func GetWeather(city *types.City, apiKey string) (types.Weather, float64, error)
 
 	// Structure representing the *current* weather
 	type WeatherRes struct {
-		Current struct {
+		Timezone       string `json:"timezone"`
+		TimezoneOffset int    `json:"timezone_offset"`
+		Current        struct {
 			FeelsLike   float64 `json:"feels_like"`
 			Temperature float64 `json:"temp"`
 			Timestamp   int64   `json:"dt"`
@@ -119,10 +123,10 @@ func GetWeather(city *types.City, apiKey string) (types.Weather, float64, error)
 	for _, alert := range weather.Alerts {
 		// Format both start and end timestamp as 'YYYY-MM-DD'
 		utcStartDate := time.Unix(int64(alert.Start), 0)
-		startDate := types.ZephyrAlertDate{Date: utcStartDate}
+		startDate := types.ZephyrAlertDate{Date: utcStartDate.UTC()}
 
 		utcEndDate := time.Unix(int64(alert.End), 0)
-		endDate := types.ZephyrAlertDate{Date: utcEndDate}
+		endDate := types.ZephyrAlertDate{Date: utcEndDate.UTC()}
 
 		// Extract the first line of alert description
 		eventDescription := strings.Split(alert.Description, "\n")[0]
@@ -136,13 +140,15 @@ func GetWeather(city *types.City, apiKey string) (types.Weather, float64, error)
 	}
 
 	return types.Weather{
-		Date:        weatherDate,
-		Temperature: strconv.FormatFloat(weather.Current.Temperature, 'f', -1, 64),
-		Min:         strconv.FormatFloat(weather.Daily[0].Temp.Min, 'f', -1, 64),
-		Max:         strconv.FormatFloat(weather.Daily[0].Temp.Max, 'f', -1, 64),
-		FeelsLike:   strconv.FormatFloat(weather.Current.FeelsLike, 'f', -1, 64),
-		Condition:   weather.Current.Weather[0].Title,
-		Emoji:       emoji,
-		Alerts:      alerts,
+		Date:           weatherDate,
+		Timezone:       weather.Timezone,
+		TimezoneOffset: weather.TimezoneOffset,
+		Temperature:    strconv.FormatFloat(weather.Current.Temperature, 'f', -1, 64),
+		Min:            strconv.FormatFloat(weather.Daily[0].Temp.Min, 'f', -1, 64),
+		Max:            strconv.FormatFloat(weather.Daily[0].Temp.Max, 'f', -1, 64),
+		FeelsLike:      strconv.FormatFloat(weather.Current.FeelsLike, 'f', -1, 64),
+		Condition:      weather.Current.Weather[0].Title,
+		Emoji:          emoji,
+		Alerts:         alerts,
 	}, weather.Daily[0].Temp.Daily, nil
 }
@@ -0,0 +1,175 @@
+package render
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// Palette of e-paper displays, grays are dithered
+var EinkTheme = Theme{
+	Background: color.RGBA{0xFF, 0xFF, 0xFF, 0xFF},
+	Foreground: color.RGBA{0x00, 0x00, 0x00, 0xFF},
+	Muted:      color.RGBA{0x30, 0x30, 0x30, 0xFF},
+	Sun:        color.RGBA{0x40, 0x40, 0x40, 0xFF},
+	Cloud:      color.RGBA{0xC8, 0xC8, 0xC8, 0xFF},
+	Rain:       color.RGBA{0x00, 0x00, 0x00, 0xFF},
+	Snow:       color.RGBA{0x50, 0x50, 0x50, 0xFF},
+}
+
+// Returns a theme with inverted colors(e.g. white text on a black background)
+func InvertTheme(theme Theme) Theme {
+	invert := func(c color.RGBA) color.RGBA {
+		return color.RGBA{0xFF - c.R, 0xFF - c.G, 0xFF - c.B, 0xFF}
+	}
+
+	return Theme{
+		Background: invert(theme.Background),
+		Foreground: invert(theme.Foreground),
+		Muted:      invert(theme.Muted),
+		Sun:        invert(theme.Sun),
+		Cloud:      invert(theme.Cloud),
+		Rain:       invert(theme.Rain),
+		Snow:       invert(theme.Snow),
+	}
+}
+
+// Bitmap, representing a monochrome image. Set pixels are black
+type Bitmap struct {
+	Width  int
+	Height int
+	Pix    []bool
+}
+
+func (bitmap *Bitmap) At(x, y int) bool {
+	return bitmap.Pix[y*bitmap.Width+x]
+}
+
+// Converts an image to a monochrome bitmap using the Floyd-Steinberg dithering
+func Dither(img image.Image) *Bitmap {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Luminance of each pixel, ranging from 0(black) to 255(white)
+	luma := make([]float64, width*height)
+	for y := range height {
+		for x := range width {
+			c := color.RGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.RGBA)
+			luma[y*width+x] = 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+		}
+	}
+
+	diffuse := func(x, y int, err float64) {
+		if x >= 0 && x < width && y < height {
+			luma[y*width+x] += err
+		}
+	}
+
+	bitmap := &Bitmap{Width: width, Height: height, Pix: make([]bool, width*height)}
+	for y := range height {
+		for x := range width {
+			old := luma[y*width+x]
+			value := 255.0
+			if old < 128 {
+				value = 0
+				bitmap.Pix[y*width+x] = true
+			}
+
+			err := old - value
+			diffuse(x+1, y, err*7/16)
+			diffuse(x-1, y+1, err*3/16)
+			diffuse(x, y+1, err*5/16)
+			diffuse(x+1, y+1, err*1/16)
+		}
+	}
+
+	return bitmap
+}
+
+// Returns the rows of the bitmap packed as 8 pixels per byte(the most significant
+// bit is the leftmost pixel), each row is padded to a whole number of bytes
+func (bitmap *Bitmap) Packed() []byte {
+	stride := (bitmap.Width + 7) / 8
+	packed := make([]byte, stride*bitmap.Height)
+
+	for y := range bitmap.Height {
+		for x := range bitmap.Width {
+			if bitmap.At(x, y) {
+				packed[y*stride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+
+	return packed
+}
+
+// Encodes the bitmap as a binary PBM(P4) image
+func (bitmap *Bitmap) EncodePBM(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "P4\n%d %d\n", bitmap.Width, bitmap.Height); err != nil {
+		return err
+	}
+
+	_, err := w.Write(bitmap.Packed())
+
+	return err
+}
+
+// Encodes the bitmap as a 1 bit per pixel BMP image
+func (bitmap *Bitmap) EncodeBMP(w io.Writer) error {
+	stride := (bitmap.Width + 7) / 8
+	paddedStride := (stride + 3) &^ 3 // BMP rows are aligned to 4 bytes
+	const headerSize = 14 + 40 + 2*4  // File header, info header and palette
+	imageSize := paddedStride * bitmap.Height
+
+	header := struct {
+		// BITMAPFILEHEADER
+		Signature  [2]byte
+		FileSize   uint32
+		Reserved   uint32
+		DataOffset uint32
+		// BITMAPINFOHEADER
+		InfoSize        uint32
+		Width           int32
+		Height          int32
+		Planes          uint16
+		BitCount        uint16
+		Compression     uint32
+		ImageSize       uint32
+		XPixelsPerMeter int32
+		YPixelsPerMeter int32
+		ColorsUsed      uint32
+		ColorsImportant uint32
+		// Palette(BGRA), index 0 is white and index 1 is black
+		Palette [2][4]byte
+	}{
+		Signature:  [2]byte{'B', 'M'},
+		FileSize:   uint32(headerSize + imageSize),
+		DataOffset: headerSize,
+		InfoSize:   40,
+		Width:      int32(bitmap.Width),
+		Height:     int32(bitmap.Height),
+		Planes:     1,
+		BitCount:   1,
+		ImageSize:  uint32(imageSize),
+		ColorsUsed: 2,
+		Palette:    [2][4]byte{{0xFF, 0xFF, 0xFF, 0x00}, {0x00, 0x00, 0x00, 0x00}},
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	// Rows are stored bottom-up
+	packed := bitmap.Packed()
+	row := make([]byte, paddedStride)
+	for y := bitmap.Height - 1; y >= 0; y-- {
+		copy(row, packed[y*stride:(y+1)*stride])
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
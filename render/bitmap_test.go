@@ -0,0 +1,73 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func uniformImage(width, height int, gray uint8) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{gray, gray, gray, 0xFF}}, image.Point{}, draw.Src)
+
+	return img
+}
+
+func TestDither(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Gray          uint8
+		ExpectedBlack int
+	}{
+		{"White", 0xFF, 0},
+		{"Black", 0x00, 64},
+		{"Mid gray", 0x80, 32},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			bitmap := Dither(uniformImage(8, 8, test.Gray))
+
+			black := 0
+			for _, pix := range bitmap.Pix {
+				if pix {
+					black++
+				}
+			}
+
+			// Dithering preserves the average luminance
+			if diff := black - test.ExpectedBlack; diff < -2 || diff > 2 {
+				t.Errorf("Got %d black pixels, wanted %d", black, test.ExpectedBlack)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	// 10x2 bitmap with the first and the last pixel set
+	bitmap := &Bitmap{Width: 10, Height: 2, Pix: make([]bool, 20)}
+	bitmap.Pix[0], bitmap.Pix[19] = true, true
+
+	if got, expected := bitmap.Packed(), []byte{0x80, 0x00, 0x00, 0x40}; !bytes.Equal(got, expected) {
+		t.Errorf("Got packed bitmap %x, wanted %x", got, expected)
+	}
+
+	var pbm bytes.Buffer
+	bitmap.EncodePBM(&pbm)
+	if expected := "P4\n10 2\n\x80\x00\x00\x40"; pbm.String() != expected {
+		t.Errorf("Got PBM %q, wanted %q", pbm.String(), expected)
+	}
+
+	var bmp bytes.Buffer
+	bitmap.EncodeBMP(&bmp)
+	// Headers, palette and two rows aligned to 4 bytes(stored bottom-up)
+	if bmp.Len() != 62+2*4 {
+		t.Fatalf("Got BMP of %d bytes, wanted %d", bmp.Len(), 62+2*4)
+	}
+
+	if got, expected := bmp.Bytes()[62:], []byte{0x00, 0x40, 0, 0, 0x80, 0x00, 0, 0}; !bytes.Equal(got, expected) {
+		t.Errorf("Got BMP rows %x, wanted %x", got, expected)
+	}
+}
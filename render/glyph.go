@@ -0,0 +1,121 @@
+package render
+
+import "math"
+
+// Glyph, representing a weather icon drawn in place of an emoji
+type Glyph int
+
+const (
+	Unknown Glyph = iota
+	Sun
+	Moon
+	Cloud
+	SunWithCloud
+	Drizzle
+	Rain
+	Thunderstorm
+	Snow
+	Tornado
+)
+
+// Returns the glyph of an emoji(see model.GetEmoji)
+func GlyphFromEmoji(emoji string) Glyph {
+	switch emoji {
+	case "☀️":
+		return Sun
+	case "🌙":
+		return Moon
+	case "☁️":
+		return Cloud
+	case "🌤️", "🌥️":
+		return SunWithCloud
+	case "🌦️":
+		return Drizzle
+	case "🌧️":
+		return Rain
+	case "⛈️":
+		return Thunderstorm
+	case "☃️":
+		return Snow
+	case "🌪️":
+		return Tornado
+	}
+
+	return Unknown
+}
+
+func drawSun(surface Surface, cx, cy, radius float64, theme Theme) {
+	surface.Circle(cx, cy, radius*0.5, theme.Sun)
+
+	for idx := range 8 {
+		angle := float64(idx) * math.Pi / 4
+		surface.Line(
+			cx+math.Cos(angle)*radius*0.7, cy+math.Sin(angle)*radius*0.7,
+			cx+math.Cos(angle)*radius*0.95, cy+math.Sin(angle)*radius*0.95,
+			radius*0.1, theme.Sun,
+		)
+	}
+}
+
+func drawCloud(surface Surface, cx, cy, radius float64, theme Theme) {
+	surface.Circle(cx-radius*0.4, cy+radius*0.1, radius*0.35, theme.Cloud)
+	surface.Circle(cx+radius*0.05, cy-radius*0.12, radius*0.47, theme.Cloud)
+	surface.Circle(cx+radius*0.45, cy+radius*0.12, radius*0.33, theme.Cloud)
+	surface.Rect(cx-radius*0.4, cy+radius*0.1, radius*0.85, radius*0.35, theme.Cloud)
+}
+
+// Draws a glyph centered on (cx, cy) within a square box of the given size
+func DrawGlyph(surface Surface, glyph Glyph, cx, cy, size float64, theme Theme) {
+	radius := size / 2
+
+	// Cloud drawn above the precipitations
+	upperCloud := func() { drawCloud(surface, cx, cy-radius*0.25, radius*0.9, theme) }
+	drops := func(c Point, count int, draw func(x, y float64)) {
+		for idx := range count {
+			draw(c.X+(float64(idx)-float64(count-1)/2)*radius*0.35, c.Y)
+		}
+	}
+
+	switch glyph {
+	case Sun:
+		drawSun(surface, cx, cy, radius, theme)
+	case Moon:
+		surface.Circle(cx, cy, radius*0.6, theme.Sun)
+		surface.Circle(cx+radius*0.3, cy-radius*0.2, radius*0.5, theme.Background)
+	case Cloud:
+		drawCloud(surface, cx, cy, radius, theme)
+	case SunWithCloud:
+		drawSun(surface, cx-radius*0.3, cy-radius*0.3, radius*0.65, theme)
+		drawCloud(surface, cx+radius*0.1, cy+radius*0.15, radius*0.8, theme)
+	case Drizzle, Rain:
+		if glyph == Drizzle {
+			drawSun(surface, cx-radius*0.35, cy-radius*0.45, radius*0.5, theme)
+		}
+		upperCloud()
+		drops(Point{cx, cy + radius*0.55}, 3, func(x, y float64) {
+			surface.Line(x+radius*0.08, y-radius*0.15, x-radius*0.08, y+radius*0.15, radius*0.09, theme.Rain)
+		})
+	case Thunderstorm:
+		upperCloud()
+		surface.Polyline([]Point{
+			{cx + radius*0.1, cy + radius*0.2},
+			{cx - radius*0.15, cy + radius*0.55},
+			{cx + radius*0.1, cy + radius*0.55},
+			{cx - radius*0.1, cy + radius*0.9},
+		}, radius*0.1, theme.Sun)
+	case Snow:
+		upperCloud()
+		drops(Point{cx, cy + radius*0.6}, 3, func(x, y float64) {
+			surface.Circle(x, y, radius*0.09, theme.Snow)
+		})
+	case Tornado:
+		for idx := range 5 {
+			halfWidth := radius * (0.9 - float64(idx)*0.15)
+			y := cy - radius*0.7 + float64(idx)*radius*0.35
+			offset := float64(idx) * radius * 0.05
+			surface.Line(cx-halfWidth+offset, y, cx+halfWidth+offset, y, radius*0.12, theme.Cloud)
+		}
+	default:
+		surface.Text(cx, cy, size*0.8, "?", theme.Muted, Middle)
+	}
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"unicode/utf8"
+)
+
+// Raster, representing a surface backed by an RGBA image.
+// Shapes are anti-aliased, texts use the bitmap font
+type Raster struct {
+	Image *image.RGBA
+}
+
+func NewRaster(width, height int) *Raster {
+	return &Raster{Image: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+// Blends a color into a pixel according to its coverage
+func (r *Raster) blend(x, y int, c color.RGBA, coverage float64) {
+	if !(image.Point{x, y}.In(r.Image.Rect)) || coverage <= 0 {
+		return
+	}
+
+	coverage = min(coverage, 1)
+	old := r.Image.RGBAAt(x, y)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*coverage + float64(b)*(1-coverage)))
+	}
+
+	r.Image.SetRGBA(x, y, color.RGBA{mix(c.R, old.R), mix(c.G, old.G), mix(c.B, old.B), 0xFF})
+}
+
+func (r *Raster) Rect(x, y, w, h float64, c color.RGBA) {
+	x0, y0 := int(math.Round(x)), int(math.Round(y))
+	x1, y1 := int(math.Round(x+w)), int(math.Round(y+h))
+
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			r.blend(px, py, c, 1)
+		}
+	}
+}
+
+func (r *Raster) Circle(cx, cy, radius float64, c color.RGBA) {
+	for py := int(cy - radius - 1); py <= int(cy+radius+1); py++ {
+		for px := int(cx - radius - 1); px <= int(cx+radius+1); px++ {
+			dist := math.Hypot(float64(px)+0.5-cx, float64(py)+0.5-cy)
+			r.blend(px, py, c, radius-dist+0.5)
+		}
+	}
+}
+
+// Returns the distance between a point and a segment
+func segmentDistance(px, py, x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return math.Hypot(px-x1, py-y1)
+	}
+
+	t := max(0, min(1, ((px-x1)*dx+(py-y1)*dy)/length))
+
+	return math.Hypot(px-(x1+t*dx), py-(y1+t*dy))
+}
+
+func (r *Raster) Line(x1, y1, x2, y2, width float64, c color.RGBA) {
+	half := width / 2
+
+	for py := int(min(y1, y2) - half - 1); py <= int(max(y1, y2)+half+1); py++ {
+		for px := int(min(x1, x2) - half - 1); px <= int(max(x1, x2)+half+1); px++ {
+			dist := segmentDistance(float64(px)+0.5, float64(py)+0.5, x1, y1, x2, y2)
+			r.blend(px, py, c, half-dist+0.5)
+		}
+	}
+}
+
+func (r *Raster) Polyline(points []Point, width float64, c color.RGBA) {
+	for idx := 1; idx < len(points); idx++ {
+		r.Line(points[idx-1].X, points[idx-1].Y, points[idx].X, points[idx].Y, width, c)
+	}
+}
+
+func (r *Raster) MeasureText(size float64, text string) float64 {
+	scale := fontScale(size)
+	count := utf8.RuneCountInString(text)
+	if count == 0 {
+		return 0
+	}
+
+	// Each character is 5 pixels wide plus 1 pixel of spacing
+	return float64(count*6*scale - scale)
+}
+
+func (r *Raster) Text(x, y, size float64, text string, c color.RGBA, anchor Anchor) {
+	scale := fontScale(size)
+	width := r.MeasureText(size, text)
+
+	switch anchor {
+	case Middle:
+		x -= width / 2
+	case End:
+		x -= width
+	}
+
+	left := int(math.Round(x))
+	top := int(math.Round(y - 3.5*float64(scale)))
+
+	for _, char := range text {
+		glyph := fontGlyph(char)
+		for col, bits := range glyph {
+			for row := range 7 {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+
+				for sy := range scale {
+					for sx := range scale {
+						r.blend(left+col*scale+sx, top+row*scale+sy, c, 1)
+					}
+				}
+			}
+		}
+
+		left += 6 * scale
+	}
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"errors"
+	"image/color"
+)
+
+// Anchor, representing the horizontal alignment of a text
+type Anchor int
+
+const (
+	Start Anchor = iota
+	Middle
+	End
+)
+
+// Point, representing a point of a surface
+type Point struct {
+	X, Y float64
+}
+
+// Surface, representing a drawing target. Coordinates are expressed in pixels
+// from the top-left corner, texts are vertically centered on their y coordinate
+type Surface interface {
+	Rect(x, y, w, h float64, c color.RGBA)
+	Circle(cx, cy, radius float64, c color.RGBA)
+	Line(x1, y1, x2, y2, width float64, c color.RGBA)
+	Polyline(points []Point, width float64, c color.RGBA)
+	Text(x, y, size float64, text string, c color.RGBA, anchor Anchor)
+	MeasureText(size float64, text string) float64
+}
+
+// Theme, representing the palette of a rendering
+type Theme struct {
+	Background color.RGBA
+	Foreground color.RGBA
+	Muted      color.RGBA
+	Sun        color.RGBA
+	Cloud      color.RGBA
+	Rain       color.RGBA
+	Snow       color.RGBA
+}
+
+var LightTheme = Theme{
+	Background: color.RGBA{0xFF, 0xFF, 0xFF, 0xFF},
+	Foreground: color.RGBA{0x1F, 0x29, 0x33, 0xFF},
+	Muted:      color.RGBA{0x7B, 0x87, 0x94, 0xFF},
+	Sun:        color.RGBA{0xF5, 0xA6, 0x23, 0xFF},
+	Cloud:      color.RGBA{0x9A, 0xA5, 0xB1, 0xFF},
+	Rain:       color.RGBA{0x3B, 0x82, 0xF6, 0xFF},
+	Snow:       color.RGBA{0x93, 0xC5, 0xFD, 0xFF},
+}
+
+var DarkTheme = Theme{
+	Background: color.RGBA{0x1F, 0x29, 0x33, 0xFF},
+	Foreground: color.RGBA{0xF5, 0xF7, 0xFA, 0xFF},
+	Muted:      color.RGBA{0x9A, 0xA5, 0xB1, 0xFF},
+	Sun:        color.RGBA{0xFB, 0xBF, 0x24, 0xFF},
+	Cloud:      color.RGBA{0xCB, 0xD2, 0xD9, 0xFF},
+	Rain:       color.RGBA{0x60, 0xA5, 0xFA, 0xFF},
+	Snow:       color.RGBA{0xE0, 0xF2, 0xFE, 0xFF},
+}
+
+func ParseTheme(theme string) (Theme, error) {
+	switch theme {
+	case "", "light":
+		return LightTheme, nil
+	case "dark":
+		return DarkTheme, nil
+	}
+
+	return Theme{}, errors.New("unsupported theme, use either 'light' or 'dark'")
+}
+
+// Returns the largest size(not greater than the given one) whose text fits the width
+func fitText(surface Surface, text string, size, maxWidth float64) float64 {
+	for size > 6 && surface.MeasureText(size, text) > maxWidth {
+		size *= 0.9
+	}
+
+	return size
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+)
+
+// SVG, representing a surface producing an SVG document
+type SVG struct {
+	width, height int
+	body          bytes.Buffer
+}
+
+func NewSVG(width, height int) *SVG {
+	return &SVG{width: width, height: height}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (svg *SVG) Rect(x, y, w, h float64, c color.RGBA) {
+	fmt.Fprintf(&svg.body, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`, x, y, w, h, hexColor(c))
+}
+
+func (svg *SVG) Circle(cx, cy, radius float64, c color.RGBA) {
+	fmt.Fprintf(&svg.body, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`, cx, cy, radius, hexColor(c))
+}
+
+func (svg *SVG) Line(x1, y1, x2, y2, width float64, c color.RGBA) {
+	fmt.Fprintf(&svg.body,
+		`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f" stroke-linecap="round"/>`,
+		x1, y1, x2, y2, hexColor(c), width,
+	)
+}
+
+func (svg *SVG) Polyline(points []Point, width float64, c color.RGBA) {
+	coords := make([]string, 0, len(points))
+	for _, point := range points {
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", point.X, point.Y))
+	}
+
+	fmt.Fprintf(&svg.body,
+		`<polyline points="%s" fill="none" stroke="%s" stroke-width="%.1f" stroke-linecap="round" stroke-linejoin="round"/>`,
+		strings.Join(coords, " "), hexColor(c), width,
+	)
+}
+
+func (svg *SVG) MeasureText(size float64, text string) float64 {
+	// Approximate advance of a monospaced font
+	return float64(utf8.RuneCountInString(text)) * size * 0.6
+}
+
+func (svg *SVG) Text(x, y, size float64, text string, c color.RGBA, anchor Anchor) {
+	textAnchor := map[Anchor]string{Start: "start", Middle: "middle", End: "end"}[anchor]
+
+	fmt.Fprintf(&svg.body,
+		`<text x="%.1f" y="%.1f" font-size="%.1f" fill="%s" text-anchor="%s" dominant-baseline="central">`,
+		x, y, size, hexColor(c), textAnchor,
+	)
+	xml.EscapeText(&svg.body, []byte(text))
+	svg.body.WriteString("</text>")
+}
+
+// Returns the SVG document
+func (svg *SVG) Bytes() []byte {
+	var doc bytes.Buffer
+
+	fmt.Fprintf(&doc,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="DejaVu Sans Mono, Menlo, monospace">`,
+		svg.width, svg.height, svg.width, svg.height,
+	)
+	doc.Write(svg.body.Bytes())
+	doc.WriteString("</svg>\n")
+
+	return doc.Bytes()
+}
@@ -0,0 +1,136 @@
+package render
+
+import "fmt"
+
+// WidgetDay, representing a day of the forecast strip of a widget
+type WidgetDay struct {
+	Label string
+	Glyph Glyph
+	Min   string
+	Max   string
+}
+
+// Widget, representing the (already formatted) content of a weather widget
+type Widget struct {
+	City        string
+	Date        string
+	Glyph       Glyph
+	Temperature string
+	Condition   string
+	Min         string
+	Max         string
+	Days        []WidgetDay
+	// Hourly temperatures of the sparkline and their labels
+	Hourly    []float64
+	HourlyMin string
+	HourlyMax string
+}
+
+// Minimum and maximum size of a widget
+const (
+	MinWidth  = 160
+	MinHeight = 100
+	MaxWidth  = 1600
+	MaxHeight = 1200
+)
+
+// Parses the size of a widget in the 'WxH' format
+func ParseSize(size string, defaultWidth, defaultHeight int) (int, int, error) {
+	if size == "" {
+		return defaultWidth, defaultHeight, nil
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("invalid size '%s', use the 'WxH' format", size)
+	}
+
+	if width < MinWidth || width > MaxWidth || height < MinHeight || height > MaxHeight {
+		return 0, 0, fmt.Errorf("size must be between %dx%d and %dx%d", MinWidth, MinHeight, MaxWidth, MaxHeight)
+	}
+
+	return width, height, nil
+}
+
+// Draws the sparkline of the hourly temperatures within a box
+func drawSparkline(surface Surface, values []float64, x, y, w, h float64, theme Theme) {
+	if len(values) < 2 {
+		return
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, val := range values {
+		lowest, highest = min(lowest, val), max(highest, val)
+	}
+
+	// Avoid dividing by zero on constant temperatures
+	span := max(highest-lowest, 1)
+
+	points := make([]Point, 0, len(values))
+	for idx, val := range values {
+		points = append(points, Point{
+			X: x + w*float64(idx)/float64(len(values)-1),
+			Y: y + h - h*(val-lowest)/span,
+		})
+	}
+
+	surface.Polyline(points, max(1.5, h*0.06), theme.Rain)
+}
+
+// Draws a widget showing the current conditions, an hourly
+// temperature sparkline and a 4-day forecast strip
+func DrawWidget(surface Surface, widget Widget, width, height int, theme Theme) {
+	w, h := float64(width), float64(height)
+	pad := h * 0.05
+
+	surface.Rect(0, 0, w, h, theme.Background)
+
+	// Current conditions
+	topH := h * 0.42
+	DrawGlyph(surface, widget.Glyph, pad+topH*0.45, pad+topH*0.45, topH*0.85, theme)
+
+	textX := pad + topH
+	textW := w/2 - textX
+	surface.Text(textX, pad+topH*0.3, fitText(surface, widget.Temperature, topH*0.38, textW), widget.Temperature, theme.Foreground, Start)
+	surface.Text(textX, pad+topH*0.66, fitText(surface, widget.Condition, topH*0.15, textW), widget.Condition, theme.Muted, Start)
+
+	minMax := widget.Min + " / " + widget.Max
+	surface.Text(textX, pad+topH*0.88, fitText(surface, minMax, topH*0.13, textW), minMax, theme.Muted, Start)
+
+	headerW := w/2 - pad
+	surface.Text(w-pad, pad+topH*0.15, fitText(surface, widget.City, topH*0.17, headerW), widget.City, theme.Foreground, End)
+	surface.Text(w-pad, pad+topH*0.4, fitText(surface, widget.Date, topH*0.12, headerW), widget.Date, theme.Muted, End)
+
+	// Hourly sparkline
+	sparkY := pad + topH + pad*0.5
+	sparkH := h * 0.16
+	labelSize := sparkH * 0.35
+	labelW := max(surface.MeasureText(labelSize, widget.HourlyMin), surface.MeasureText(labelSize, widget.HourlyMax))
+
+	surface.Line(pad, sparkY, w-pad, sparkY, 1, theme.Muted)
+	drawSparkline(surface, widget.Hourly, pad, sparkY+sparkH*0.2, w-3*pad-labelW, sparkH*0.7, theme)
+	if len(widget.Hourly) >= 2 {
+		surface.Text(w-pad, sparkY+sparkH*0.3, labelSize, widget.HourlyMax, theme.Muted, End)
+		surface.Text(w-pad, sparkY+sparkH*0.8, labelSize, widget.HourlyMin, theme.Muted, End)
+	}
+
+	// Forecast strip
+	stripY := sparkY + sparkH + pad*0.5
+	stripH := h - stripY - pad
+	surface.Line(pad, stripY, w-pad, stripY, 1, theme.Muted)
+
+	days := widget.Days[:min(len(widget.Days), 4)]
+	if len(days) == 0 {
+		return
+	}
+
+	colW := (w - 2*pad) / float64(len(days))
+	for idx, day := range days {
+		cx := pad + colW*(float64(idx)+0.5)
+		temps := day.Max + " " + day.Min
+
+		surface.Text(cx, stripY+stripH*0.15, fitText(surface, day.Label, stripH*0.16, colW*0.9), day.Label, theme.Foreground, Middle)
+		DrawGlyph(surface, day.Glyph, cx, stripY+stripH*0.5, stripH*0.42, theme)
+		surface.Text(cx, stripY+stripH*0.87, fitText(surface, temps, stripH*0.15, colW*0.9), temps, theme.Muted, Middle)
+	}
+}
@@ -0,0 +1,54 @@
+package render
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Input          string
+		ExpectedWidth  int
+		ExpectedHeight int
+		ExpectedErr    bool
+	}{
+		{"Default size", "", 400, 240, false},
+		{"Custom size", "800x480", 800, 480, false},
+		{"Malformed size", "800", 0, 0, true},
+		{"Too small", "10x10", 0, 0, true},
+		{"Too large", "4000x3000", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			width, height, err := ParseSize(test.Input, 400, 240)
+
+			if (err != nil) != test.ExpectedErr {
+				t.Fatalf("Got error %v, wanted error: %t", err, test.ExpectedErr)
+			}
+
+			if width != test.ExpectedWidth || height != test.ExpectedHeight {
+				t.Errorf("Got %dx%d, wanted %dx%d", width, height, test.ExpectedWidth, test.ExpectedHeight)
+			}
+		})
+	}
+}
+
+func TestFontGlyph(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    rune
+		Expected rune
+	}{
+		{"ASCII character", 'A', 'A'},
+		{"Diacritic", 'ì', 'i'},
+		{"Uppercase diacritic", 'É', 'E'},
+		{"Unknown character", '☃', '?'},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := fontGlyph(test.Input); got != font[test.Expected] {
+				t.Errorf("Got %v, wanted the glyph of '%c'", got, test.Expected)
+			}
+		})
+	}
+}
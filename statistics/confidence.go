@@ -0,0 +1,188 @@
+package statistics
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Evaluates the continued fraction of the regularized incomplete beta function
+// using the modified Lentz's method
+func betaContinuedFraction(a, b, x float64) float64 {
+	const maxIterations = 200
+	const epsilon = 1e-14
+	const tiny = 1e-300
+
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		// Even step
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
+
+// Computes the regularized incomplete beta function I_x(a, b)
+func regularizedBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgammaAB, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges rapidly for x < (a+1)/(a+b+2),
+	// otherwise we use the symmetry relation I_x(a, b) = 1 - I_{1-x}(b, a)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// Computes the cumulative distribution function of the
+// Student's t-distribution with df degrees of freedom
+func studentTCDF(t, df float64) float64 {
+	tail := 0.5 * regularizedBeta(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+
+	return tail
+}
+
+// Computes the quantile function of the Student's t-distribution
+// with df degrees of freedom by bisecting its CDF
+func StudentTQuantile(p, df float64) float64 {
+	if p <= 0 || p >= 1 || df <= 0 {
+		return math.NaN()
+	}
+
+	// Expand the bounds until they enclose the quantile
+	low, high := -1.0, 1.0
+	for studentTCDF(low, df) > p {
+		low *= 2
+	}
+	for studentTCDF(high, df) < p {
+		high *= 2
+	}
+
+	for range 200 {
+		mid := (low + high) / 2
+		if studentTCDF(mid, df) < p {
+			low = mid
+		} else {
+			high = mid
+		}
+
+		if high-low < 1e-12 {
+			break
+		}
+	}
+
+	return (low + high) / 2
+}
+
+// Computes the t-based confidence interval of the mean at a given
+// confidence level(e.g. 0.95)
+//
+// The interval is given by x̄ ± t_{(1+level)/2, n-1} * s/√n, where s is the
+// sample(i.e. Bessel-corrected) standard deviation.
+// The third return value is false when the interval is undefined(less than two samples).
+func MeanConfidenceInterval(temperatures []float64, level float64) (float64, float64, bool) {
+	length := len(temperatures)
+	if length < 2 || level <= 0 || level >= 1 {
+		return 0, 0, false
+	}
+
+	mean := Mean(temperatures)
+	sampleStdDev := StdDev(temperatures) * math.Sqrt(float64(length)/float64(length-1))
+	margin := StudentTQuantile((1+level)/2, float64(length-1)) * sampleStdDev / math.Sqrt(float64(length))
+
+	return mean - margin, mean + margin, true
+}
+
+// Computes the bootstrap(percentile method) confidence interval of the median
+// at a given confidence level(e.g. 0.95)
+//
+// The sample is resampled with replacement a fixed number of times; the bounds
+// of the interval are the (1-level)/2 and (1+level)/2 percentiles of the resampled medians.
+// The random generator uses a fixed seed, so that the same sample always yields the same interval.
+// The third return value is false when the interval is undefined(less than two samples).
+func MedianConfidenceInterval(temperatures []float64, level float64) (float64, float64, bool) {
+	const iterations = 2000
+	const seed = 42
+
+	length := len(temperatures)
+	if length < 2 || level <= 0 || level >= 1 {
+		return 0, 0, false
+	}
+
+	// Sort the array without mutating the original values, so that
+	// the resampling does not depend on the order of the records
+	sortedTemps := slices.Clone(temperatures)
+	slices.Sort(sortedTemps)
+
+	r := rand.New(rand.NewSource(seed))
+	resample := make([]float64, length)
+	medians := make([]float64, iterations)
+
+	for i := range iterations {
+		for j := range resample {
+			resample[j] = sortedTemps[r.Intn(length)]
+		}
+		medians[i] = Median(resample)
+	}
+	slices.Sort(medians)
+
+	percentile := func(p float64) float64 {
+		idx := int(math.Round(p * float64(iterations-1)))
+		return medians[idx]
+	}
+
+	return percentile((1 - level) / 2), percentile((1 + level) / 2), true
+}
@@ -0,0 +1,59 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentTQuantile(t *testing.T) {
+	tests := []struct {
+		Name     string
+		P        float64
+		DF       float64
+		Expected float64
+	}{
+		{"Median", 0.5, 10, 0},
+		{"One degree of freedom", 0.975, 1, 12.706204736},
+		{"Small sample", 0.975, 4, 2.776445105},
+		{"Large sample", 0.95, 30, 1.697260887},
+		{"Lower tail", 0.025, 9, -2.262157163},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := StudentTQuantile(test.P, test.DF)
+			if math.Abs(got-test.Expected) > 1e-6 {
+				t.Errorf("Got %v, wanted %v", got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestMeanConfidenceInterval(t *testing.T) {
+	// Mean 3, sample standard deviation √2.5
+	lower, upper, found := MeanConfidenceInterval([]float64{1, 2, 3, 4, 5}, 0.95)
+	margin := 2.776445105 * math.Sqrt(2.5) / math.Sqrt(5)
+
+	if !found || math.Abs(lower-(3-margin)) > 1e-6 || math.Abs(upper-(3+margin)) > 1e-6 {
+		t.Errorf("Got (%v, %v, %v), wanted (%v, %v, true)", lower, upper, found, 3-margin, 3+margin)
+	}
+
+	if _, _, found := MeanConfidenceInterval([]float64{1}, 0.95); found {
+		t.Errorf("Got a confidence interval for a single sample")
+	}
+}
+
+func TestMedianConfidenceInterval(t *testing.T) {
+	temps := []float64{18, 19, 19, 20, 20, 21, 21, 21, 22, 22, 23, 24}
+
+	lower, upper, found := MedianConfidenceInterval(temps, 0.95)
+	if !found || lower > Median(temps) || upper < Median(temps) || lower < 18 || upper > 24 {
+		t.Errorf("Got (%v, %v, %v), wanted an interval enclosing %v", lower, upper, found, Median(temps))
+	}
+
+	// Same sample, same interval
+	secondLower, secondUpper, _ := MedianConfidenceInterval(temps, 0.95)
+	if lower != secondLower || upper != secondUpper {
+		t.Errorf("Got (%v, %v), wanted (%v, %v)", secondLower, secondUpper, lower, upper)
+	}
+}
@@ -0,0 +1,58 @@
+package statistics
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+// Computes the 3-hour barometric pressure tendency(expressed in hPa/3h)
+//
+// The most recent reading is compared against the reading closest to three hours
+// before it. Since readings are only collected when clients request them, the reference
+// reading can be anywhere between one hour and maxAge old(i.e. six hours, or more when
+// readings are collected less often); the pressure change is then scaled linearly to a
+// three hours window.
+//
+// The second return value is false when there isn't a suitable reference reading.
+func PressureTendency(readings []types.PressureElement, maxAge time.Duration) (float64, bool) {
+	const window = 3 * time.Hour
+	const minAge = 1 * time.Hour
+
+	if len(readings) < 2 {
+		return 0, false
+	}
+
+	// Sort readings chronologically without mutating the original values
+	sortedReadings := slices.Clone(readings)
+	slices.SortFunc(sortedReadings, func(a, b types.PressureElement) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	latest := sortedReadings[len(sortedReadings)-1]
+
+	var reference *types.PressureElement
+	for idx := range sortedReadings[:len(sortedReadings)-1] {
+		age := latest.Timestamp.Sub(sortedReadings[idx].Timestamp)
+		if age < minAge || age > maxAge {
+			continue
+		}
+
+		if reference == nil ||
+			(age-window).Abs() < (latest.Timestamp.Sub(reference.Timestamp)-window).Abs() {
+			reference = &sortedReadings[idx]
+		}
+	}
+
+	if reference == nil {
+		return 0, false
+	}
+
+	age := latest.Timestamp.Sub(reference.Timestamp)
+	rate := (latest.Pressure - reference.Pressure) * (float64(window) / float64(age))
+
+	// Round to the first decimal digit
+	return math.Round(rate*10) / 10, true
+}
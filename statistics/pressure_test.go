@@ -0,0 +1,45 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ceticamarco/zephyr/types"
+)
+
+func TestPressureTendency(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reading := func(hoursAgo float64, pressure float64) types.PressureElement {
+		return types.PressureElement{
+			Pressure:  pressure,
+			Timestamp: now.Add(-time.Duration(hoursAgo * float64(time.Hour))),
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		Input    []types.PressureElement
+		MaxAge   time.Duration
+		Expected float64
+		Found    bool
+	}{
+		{"Empty history", []types.PressureElement{}, 6 * time.Hour, 0, false},
+		{"Single reading", []types.PressureElement{reading(0, 1013)}, 6 * time.Hour, 0, false},
+		{"Reference too recent", []types.PressureElement{reading(0.5, 1015), reading(0, 1013)}, 6 * time.Hour, 0, false},
+		{"Reference too old", []types.PressureElement{reading(8, 1015), reading(0, 1013)}, 6 * time.Hour, 0, false},
+		{"Falling pressure", []types.PressureElement{reading(3, 1015), reading(0, 1010)}, 6 * time.Hour, -5, true},
+		{"Scaled rising pressure", []types.PressureElement{reading(6, 1008), reading(0, 1012)}, 6 * time.Hour, 2, true},
+		{"Closest reference", []types.PressureElement{reading(5, 1000), reading(3, 1012), reading(0, 1013)}, 6 * time.Hour, 1, true},
+		{"Unsorted history", []types.PressureElement{reading(0, 1013), reading(3, 1012)}, 6 * time.Hour, 1, true},
+		{"Longer cache TTL", []types.PressureElement{reading(12, 1016), reading(0, 1012)}, 24 * time.Hour, -1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, found := PressureTendency(test.Input, test.MaxAge)
+			if found != test.Found || !cmpVal(got, test.Expected) {
+				t.Errorf("Got (%v, %v), wanted (%v, %v)", got, found, test.Expected, test.Found)
+			}
+		})
+	}
+}
@@ -165,3 +165,30 @@ func DetectAnomalies(statsArr []types.StatElement) []types.WeatherAnomaly {
 
 	return result
 }
+
+// Computes the Pearson correlation coefficient of two paired samples
+//
+// The second return value is false when the coefficient is undefined, that is
+// when the samples have different lengths, less than two elements or
+// when one of them has zero variance.
+func Pearson(x, y []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0, false
+	}
+
+	meanX, meanY := Mean(x), Mean(y)
+
+	var covariance, varianceX, varianceY float64
+	for idx := range x {
+		devX, devY := x[idx]-meanX, y[idx]-meanY
+		covariance += devX * devY
+		varianceX += devX * devX
+		varianceY += devY * devY
+	}
+
+	if varianceX == 0 || varianceY == 0 {
+		return 0, false
+	}
+
+	return covariance / math.Sqrt(varianceX*varianceY), true
+}
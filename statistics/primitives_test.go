@@ -121,3 +121,29 @@ func TestRobustZScore(t *testing.T) {
 		})
 	}
 }
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		Name     string
+		X        []float64
+		Y        []float64
+		Expected float64
+		Found    bool
+	}{
+		{"Empty lists", []float64{}, []float64{}, 0, false},
+		{"Mismatched lengths", []float64{1.0, 2.0}, []float64{1.0}, 0, false},
+		{"Zero variance", []float64{1.0, 2.0, 3.0}, []float64{4.0, 4.0, 4.0}, 0, false},
+		{"Perfect correlation", []float64{1.0, 2.0, 3.0}, []float64{2.0, 4.0, 6.0}, 1.0, true},
+		{"Perfect anticorrelation", []float64{1.0, 2.0, 3.0}, []float64{3.0, 2.0, 1.0}, -1.0, true},
+		{"Partial correlation", []float64{1.0, 2.0, 3.0, 4.0}, []float64{2.0, 1.0, 4.0, 3.0}, 0.6, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, found := Pearson(test.X, test.Y)
+			if found != test.Found || !cmpVal(got, test.Expected) {
+				t.Errorf("Got (%v, %v), wanted (%v, %v)", got, found, test.Expected, test.Found)
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@ package types
 import (
 	"strings"
 	"time"
+
+	"github.com/ceticamarco/zephyr/i18n"
 )
 
 type ZephyrDate struct {
 	Date time.Time
+	Lang i18n.Language
 }
 
 func (date *ZephyrDate) UnmarshalJSON(b []byte) error {
@@ -24,18 +27,21 @@ func (date *ZephyrDate) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (date ZephyrDate) MarshalJSON() ([]byte, error) {
+func (date ZephyrDate) String() string {
 	if date.Date.IsZero() {
-		return []byte("\"\""), nil
+		return ""
 	}
 
-	fmtDate := date.Date.Format("Monday, 2006/01/02")
+	return i18n.Format(date.Date, "Monday, 2006/01/02", date.Lang)
+}
 
-	return []byte("\"" + fmtDate + "\""), nil
+func (date ZephyrDate) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + date.String() + "\""), nil
 }
 
 type ZephyrTime struct {
 	Time time.Time
+	Lang i18n.Language
 }
 
 func (t *ZephyrTime) UnmarshalJSON(b []byte) error {
@@ -53,18 +59,21 @@ func (t *ZephyrTime) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (t ZephyrTime) MarshalJSON() ([]byte, error) {
+func (t ZephyrTime) String() string {
 	if t.Time.IsZero() {
-		return []byte("\"\""), nil
+		return ""
 	}
 
-	fmtTime := t.Time.Format("3:04 PM")
+	return i18n.Format(t.Time, i18n.TimeLayout(t.Lang), t.Lang)
+}
 
-	return []byte("\"" + fmtTime + "\""), nil
+func (t ZephyrTime) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + t.String() + "\""), nil
 }
 
 type ZephyrAlertDate struct {
 	Date time.Time
+	Lang i18n.Language
 }
 
 func (t *ZephyrAlertDate) UnmarshalJSON(b []byte) error {
@@ -82,12 +91,14 @@ func (t *ZephyrAlertDate) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (t ZephyrAlertDate) MarshalJSON() ([]byte, error) {
+func (t ZephyrAlertDate) String() string {
 	if t.Date.IsZero() {
-		return []byte("\"\""), nil
+		return ""
 	}
 
-	fmtTime := t.Date.Format("Monday, 2006/01/02 3:04 PM")
+	return i18n.Format(t.Date, "Monday, 2006/01/02 "+i18n.TimeLayout(t.Lang), t.Lang)
+}
 
-	return []byte("\"" + fmtTime + "\""), nil
+func (t ZephyrAlertDate) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + t.String() + "\""), nil
 }
@@ -0,0 +1,162 @@
+package types
+
+import "time"
+
+// Data types of the version 2 API. Unlike the types of the first version,
+// values are numeric, paired with an explicit unit and timestamps
+// follow the ISO 8601 format.
+
+// The Measure data type, representing a numeric value along with its unit
+type Measure struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// The WeatherAlertV2 data type, representing a weather alert
+type WeatherAlertV2 struct {
+	Event       string    `json:"event"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Description string    `json:"description"`
+}
+
+// The WeatherV2 data type, representing the weather of a certain location
+type WeatherV2 struct {
+	Timestamp   time.Time        `json:"timestamp"`
+	Timezone    string           `json:"timezone"`
+	Temperature Measure          `json:"temperature"`
+	Min         Measure          `json:"min"`
+	Max         Measure          `json:"max"`
+	Condition   string           `json:"condition"`
+	FeelsLike   Measure          `json:"feelsLike"`
+	Emoji       string           `json:"emoji"`
+	Alerts      []WeatherAlertV2 `json:"alerts"`
+	Location    *City            `json:"location"`
+}
+
+// The PressureTendencyV2 data type, representing the 3-hour
+// barometric pressure tendency of a location
+type PressureTendencyV2 struct {
+	Trend     string  `json:"trend"`
+	Rate      Measure `json:"rate"`
+	RapidFall bool    `json:"rapidFall"`
+}
+
+// The MetricsV2 data type, representing the humidity, pressure and
+// similar miscellaneous values
+type MetricsV2 struct {
+	Humidity   Measure             `json:"humidity"`
+	Pressure   Measure             `json:"pressure"`
+	DewPoint   Measure             `json:"dewPoint"`
+	UvIndex    float64             `json:"uvIndex"`
+	Visibility Measure             `json:"visibility"`
+	Tendency   *PressureTendencyV2 `json:"pressureTendency"`
+}
+
+// The WindV2 data type, representing the wind of a certain location
+type WindV2 struct {
+	Arrow     string  `json:"arrow"`
+	Direction string  `json:"direction"`
+	Speed     Measure `json:"speed"`
+}
+
+// The DailyForecastEntityV2 data type, representing the weather forecast
+// of a single day. The date is formatted as 'YYYY-MM-DD'
+type DailyForecastEntityV2 struct {
+	Date      string  `json:"date"`
+	Min       Measure `json:"min"`
+	Max       Measure `json:"max"`
+	Condition string  `json:"condition"`
+	Emoji     string  `json:"emoji"`
+	FeelsLike Measure `json:"feelsLike"`
+	Wind      WindV2  `json:"wind"`
+	RainProb  Measure `json:"rainProbability"`
+}
+
+// The DailyForecastV2 data type, representing a set of DailyForecastEntityV2
+type DailyForecastV2 struct {
+	Timezone string                  `json:"timezone"`
+	Forecast []DailyForecastEntityV2 `json:"forecast"`
+}
+
+func (forecast DailyForecastV2) RowsKey() string { return "forecast" }
+
+// The HourlyForecastEntityV2 data type, representing the weather forecast
+// of a single hour
+type HourlyForecastEntityV2 struct {
+	Time        time.Time `json:"time"`
+	Temperature Measure   `json:"temperature"`
+	Condition   string    `json:"condition"`
+	Emoji       string    `json:"emoji"`
+	Wind        WindV2    `json:"wind"`
+	RainProb    Measure   `json:"rainProbability"`
+}
+
+// The HourlyForecastV2 data type, representing a set of HourlyForecastEntityV2
+type HourlyForecastV2 struct {
+	Timezone string                   `json:"timezone"`
+	Forecast []HourlyForecastEntityV2 `json:"forecast"`
+}
+
+func (forecast HourlyForecastV2) RowsKey() string { return "forecast" }
+
+// The MoonV2 data type, representing the moon phase,
+// the moon phase icon and the moon illumination
+type MoonV2 struct {
+	Icon         string  `json:"icon"`
+	Phase        string  `json:"phase"`
+	Illumination Measure `json:"illumination"`
+}
+
+// The ConfidenceIntervalV2 data type, representing the
+// confidence interval of a statistical estimate
+type ConfidenceIntervalV2 struct {
+	Level Measure `json:"level"`
+	Lower Measure `json:"lower"`
+	Upper Measure `json:"upper"`
+}
+
+// The WeatherAnomalyV2 data type, representing
+// skewed meteorological events. The date is formatted as 'YYYY-MM-DD'
+type WeatherAnomalyV2 struct {
+	Date        string  `json:"date"`
+	Temperature Measure `json:"temperature"`
+}
+
+// The StatResultV2 data type, representing weather statistics
+// of past meteorological events
+type StatResultV2 struct {
+	Min      Measure               `json:"min"`
+	Max      Measure               `json:"max"`
+	Count    int                   `json:"count"`
+	Mean     Measure               `json:"mean"`
+	MeanCI   *ConfidenceIntervalV2 `json:"meanCI"`
+	StdDev   Measure               `json:"stdDev"`
+	Median   Measure               `json:"median"`
+	MedianCI *ConfidenceIntervalV2 `json:"medianCI"`
+	Mode     Measure               `json:"mode"`
+	Anomaly  []WeatherAnomalyV2    `json:"anomaly"`
+}
+
+// The StatPeriodV2 data type, representing weather
+// statistics aggregated over a week or a month
+type StatPeriodV2 struct {
+	Period string  `json:"period"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Count  int     `json:"count"`
+	Min    Measure `json:"min"`
+	Max    Measure `json:"max"`
+	Mean   Measure `json:"mean"`
+	StdDev Measure `json:"stdDev"`
+	Median Measure `json:"median"`
+}
+
+// The GroupedStatResultV2 data type, representing weather
+// statistics grouped by week or month
+type GroupedStatResultV2 struct {
+	GroupBy string         `json:"groupBy"`
+	Periods []StatPeriodV2 `json:"periods"`
+}
+
+func (stats GroupedStatResultV2) RowsKey() string { return "periods" }
@@ -2,20 +2,76 @@ package types
 
 import "time"
 
-// Variables type, representing values read from environment variables
+// Variables type, representing values read from environment variables along with
+// the services they configure(i.e. geocoder, suggestions and aliases). Services are
+// optional, a nil service falls back to OpenWeatherMap or disables the feature
 type Variables struct {
-	Token      string
-	TimeToLive int8
+	Token        string
+	AdminToken   string
+	TimeToLive   int8
+	PressureDrop float64
+	// Geocoder used in place of OpenWeatherMap, nil selects OpenWeatherMap
+	Geocoder Geocoder
+	// Index of the known locations used for suggestions
+	Suggestions Suggestions
+	// Named locations(e.g. 'home')
+	Aliases AliasStore
+}
+
+// Geocoder, representing an alternative to the OpenWeatherMap geocoding(e.g. an offline index)
+type Geocoder interface {
+	Search(query string, limit int) []City
+}
+
+// Suggestions, representing the known locations, that is, the resolved
+// locations along with an (optional) local city list
+type Suggestions interface {
+	Autocomplete(query string, limit int) []City
+	Add(city City)
+}
+
+// AliasStore, representing the named locations
+type AliasStore interface {
+	Get(name string) (Alias, bool)
+	List() Aliases
+	Set(alias Alias) error
+	Delete(name string) (bool, error)
 }
 
 // The City data type, representing the name, the latitude and the longitude
-// of a location
+// of a location along with its state, country and names in other languages
 type City struct {
-	Name string  `json:"name"`
-	Lat  float64 `json:"lat"`
-	Lon  float64 `json:"lon"`
+	Name       string            `json:"name"`
+	State      string            `json:"state,omitempty"`
+	Country    string            `json:"country,omitempty"`
+	Lat        float64           `json:"lat"`
+	Lon        float64           `json:"lon"`
+	LocalNames map[string]string `json:"local_names,omitempty"`
+}
+
+// The Locations data type, representing the candidates of a geocoding query
+type Locations struct {
+	Locations []City `json:"locations"`
+}
+
+func (locations Locations) RowsKey() string { return "locations" }
+
+// The Alias data type, representing a named location(e.g. 'home') that
+// maps either to a set of coordinates or to a city query(e.g. 'Milan,IT')
+type Alias struct {
+	Name  string   `json:"name"`
+	Lat   *float64 `json:"lat,omitempty"`
+	Lon   *float64 `json:"lon,omitempty"`
+	Query string   `json:"query,omitempty"`
+}
+
+// The Aliases data type, representing the list of the named locations
+type Aliases struct {
+	Aliases []Alias `json:"aliases"`
 }
 
+func (aliases Aliases) RowsKey() string { return "aliases" }
+
 // The DailyForecastEntity data type, representing the weather forecast
 // of a single day
 type DailyForecastEntity struct {
@@ -31,9 +87,13 @@ type DailyForecastEntity struct {
 
 // The DailyForecast data type, representing a set of DailyForecastEntity
 type DailyForecast struct {
-	Forecast []DailyForecastEntity `json:"forecast"`
+	Timezone       string                `json:"timezone"`
+	TimezoneOffset int                   `json:"-"`
+	Forecast       []DailyForecastEntity `json:"forecast"`
 }
 
+func (forecast DailyForecast) RowsKey() string { return "forecast" }
+
 // The HourlyForecastEntity data type, representing the weather forecast
 // of a single hour
 type HourlyForecastEntity struct {
@@ -47,17 +107,37 @@ type HourlyForecastEntity struct {
 
 // The HourlyForecast data type, representing a set of HourlyForecastEntity
 type HourlyForecast struct {
-	Forecast []HourlyForecastEntity `json:"forecast"`
+	Timezone       string                 `json:"timezone"`
+	TimezoneOffset int                    `json:"-"`
+	Forecast       []HourlyForecastEntity `json:"forecast"`
+}
+
+func (forecast HourlyForecast) RowsKey() string { return "forecast" }
+
+// The PressureElement data type, representing a barometric pressure reading
+// This type is for internal usage
+type PressureElement struct {
+	Pressure  float64
+	Timestamp time.Time
+}
+
+// The PressureTendency data type, representing the 3-hour
+// barometric pressure tendency of a location
+type PressureTendency struct {
+	Trend     string `json:"trend"`
+	Rate      string `json:"rate"`
+	RapidFall bool   `json:"rapidFall"`
 }
 
 // The Metrics data type, representing the humidity, pressure and
 // similar miscellaneous values
 type Metrics struct {
-	Humidity   string `json:"humidity"`
-	Pressure   string `json:"pressure"`
-	DewPoint   string `json:"dewPoint"`
-	UvIndex    string `json:"uvIndex"`
-	Visibility string `json:"visibility"`
+	Humidity   string            `json:"humidity"`
+	Pressure   string            `json:"pressure"`
+	DewPoint   string            `json:"dewPoint"`
+	UvIndex    string            `json:"uvIndex"`
+	Visibility string            `json:"visibility"`
+	Tendency   *PressureTendency `json:"pressureTendency"`
 }
 
 // The Moon data type, representing the moon phase,
@@ -82,17 +162,84 @@ type StatElement struct {
 	Date        time.Time
 }
 
+// The ConfidenceInterval data type, representing the
+// confidence interval of a statistical estimate
+type ConfidenceInterval struct {
+	Level string `json:"level"`
+	Lower string `json:"lower"`
+	Upper string `json:"upper"`
+}
+
+// The StatRecord data type, representing a statistical record
+// as imported or exported from the statistics database
+type StatRecord struct {
+	City        string  `json:"city"`
+	Date        string  `json:"date"`
+	Temperature float64 `json:"temperature"`
+}
+
 // The StatResult data type, representing weather statistics
 // of past meteorological events
 type StatResult struct {
-	Min     string            `json:"min"`
-	Max     string            `json:"max"`
-	Count   int               `json:"count"`
-	Mean    string            `json:"mean"`
-	StdDev  string            `json:"stdDev"`
-	Median  string            `json:"median"`
-	Mode    string            `json:"mode"`
-	Anomaly *[]WeatherAnomaly `json:"anomaly"`
+	Min      string              `json:"min"`
+	Max      string              `json:"max"`
+	Count    int                 `json:"count"`
+	Mean     string              `json:"mean"`
+	MeanCI   *ConfidenceInterval `json:"meanCI"`
+	StdDev   string              `json:"stdDev"`
+	Median   string              `json:"median"`
+	MedianCI *ConfidenceInterval `json:"medianCI"`
+	Mode     string              `json:"mode"`
+	Anomaly  *[]WeatherAnomaly   `json:"anomaly"`
+}
+
+// The StatPeriod data type, representing weather
+// statistics aggregated over a week or a month
+type StatPeriod struct {
+	Period string     `json:"period"`
+	From   ZephyrDate `json:"from"`
+	To     ZephyrDate `json:"to"`
+	Count  int        `json:"count"`
+	Min    string     `json:"min"`
+	Max    string     `json:"max"`
+	Mean   string     `json:"mean"`
+	StdDev string     `json:"stdDev"`
+	Median string     `json:"median"`
+}
+
+// The GroupedStatResult data type, representing weather
+// statistics grouped by week or month
+type GroupedStatResult struct {
+	GroupBy string       `json:"groupBy"`
+	Periods []StatPeriod `json:"periods"`
+}
+
+func (stats GroupedStatResult) RowsKey() string { return "periods" }
+
+// The CityStatResult data type, representing the weather
+// statistics of a single location
+type CityStatResult struct {
+	City       string     `json:"city"`
+	Statistics StatResult `json:"statistics"`
+}
+
+// The StatPairResult data type, representing the comparison
+// of the daily series of two locations over their common dates
+type StatPairResult struct {
+	First       string     `json:"first"`
+	Second      string     `json:"second"`
+	From        ZephyrDate `json:"from"`
+	To          ZephyrDate `json:"to"`
+	Overlap     int        `json:"overlap"`
+	MeanDiff    *string    `json:"meanDifference"`
+	Correlation *string    `json:"correlation"`
+}
+
+// The StatComparison data type, representing the weather
+// statistics of multiple locations side by side
+type StatComparison struct {
+	Cities []CityStatResult `json:"cities"`
+	Pairs  []StatPairResult `json:"pairs"`
 }
 
 // The WeatherAlert data type, representing a
@@ -106,14 +253,17 @@ type WeatherAlert struct {
 
 // The Weather data type, representing the weather of a certain location
 type Weather struct {
-	Date        ZephyrDate     `json:"date"`
-	Temperature string         `json:"temperature"`
-	Min         string         `json:"min"`
-	Max         string         `json:"max"`
-	Condition   string         `json:"condition"`
-	FeelsLike   string         `json:"feelsLike"`
-	Emoji       string         `json:"emoji"`
-	Alerts      []WeatherAlert `json:"alerts"`
+	Date           ZephyrDate     `json:"date"`
+	Timezone       string         `json:"timezone"`
+	TimezoneOffset int            `json:"-"`
+	Temperature    string         `json:"temperature"`
+	Min            string         `json:"min"`
+	Max            string         `json:"max"`
+	Condition      string         `json:"condition"`
+	FeelsLike      string         `json:"feelsLike"`
+	Emoji          string         `json:"emoji"`
+	Alerts         []WeatherAlert `json:"alerts"`
+	Location       *City          `json:"location"`
 }
 
 // The Wind data type, representing the wind of a certain location
@@ -122,3 +272,48 @@ type Wind struct {
 	Direction string `json:"direction"`
 	Speed     string `json:"speed"`
 }
+
+// The Summary data type, representing the weather, metrics, wind
+// and forecasts of a certain location. Excluded sections are omitted
+type Summary struct {
+	City    string          `json:"city"`
+	Weather *Weather        `json:"weather,omitempty"`
+	Metrics *Metrics        `json:"metrics,omitempty"`
+	Wind    *Wind           `json:"wind,omitempty"`
+	Daily   *DailyForecast  `json:"daily,omitempty"`
+	Hourly  *HourlyForecast `json:"hourly,omitempty"`
+}
+
+// The BatchResult data type, representing the weather of a location
+// within a batch or the error raised while retrieving it
+type BatchResult struct {
+	City    string   `json:"city"`
+	Weather *Weather `json:"weather,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// The Batch data type, representing the weather of multiple locations
+type Batch struct {
+	Results []BatchResult `json:"results"`
+}
+
+func (batch Batch) RowsKey() string { return "results" }
+
+// The NearbyPlace data type, representing the weather of a place near a location
+// along with its distance and its bearing(in degrees) from the location
+type NearbyPlace struct {
+	City      City     `json:"city"`
+	Distance  string   `json:"distance"`
+	Bearing   int      `json:"bearing"`
+	Direction string   `json:"direction"`
+	Weather   *Weather `json:"weather,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// The Nearby data type, representing the places near a location sorted by distance
+type Nearby struct {
+	Location City          `json:"location"`
+	Places   []NearbyPlace `json:"places"`
+}
+
+func (nearby Nearby) RowsKey() string { return "places" }
@@ -0,0 +1,336 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Temperature unit
+type Temperature string
+
+const (
+	Celsius    Temperature = "°C"
+	Fahrenheit Temperature = "°F"
+	Kelvin     Temperature = "K"
+)
+
+// Speed unit
+type Speed string
+
+const (
+	KilometersPerHour Speed = "km/h"
+	MilesPerHour      Speed = "mph"
+	MetersPerSecond   Speed = "m/s"
+	Knots             Speed = "kn"
+	Beaufort          Speed = "Bft"
+)
+
+// Pressure unit
+type Pressure string
+
+const (
+	Hectopascal          Pressure = "hPa"
+	Pascal               Pressure = "Pa"
+	InchesOfMercury      Pressure = "inHg"
+	MillimetersOfMercury Pressure = "mmHg"
+)
+
+// Distance unit
+type Distance string
+
+const (
+	Kilometers Distance = "km"
+	Miles      Distance = "mi"
+	Meters     Distance = "m"
+)
+
+// System, representing the unit of each physical quantity
+type System struct {
+	Temperature Temperature
+	Speed       Speed
+	Pressure    Pressure
+	Distance    Distance
+}
+
+var (
+	Metric   = System{Celsius, KilometersPerHour, Hectopascal, Kilometers}
+	Imperial = System{Fahrenheit, MilesPerHour, InchesOfMercury, Miles}
+	SI       = System{Kelvin, MetersPerSecond, Pascal, Meters}
+)
+
+// Values are always expressed in metric units(°C, m/s, hPa and km),
+// since that's how they are retrieved from OpenWeatherMap
+
+func ConvertTemperature(celsius float64, unit Temperature) float64 {
+	switch unit {
+	case Fahrenheit:
+		return celsius*9.0/5.0 + 32
+	case Kelvin:
+		return celsius + 273.15
+	}
+
+	return celsius
+}
+
+// Converts a temperature difference(e.g. a standard deviation),
+// which does not carry the offset of the scale
+func ConvertTemperatureDelta(celsius float64, unit Temperature) float64 {
+	if unit == Fahrenheit {
+		return celsius * 9.0 / 5.0
+	}
+
+	return celsius
+}
+
+func ConvertSpeed(metersPerSecond float64, unit Speed) float64 {
+	switch unit {
+	case KilometersPerHour:
+		return metersPerSecond * 3.6
+	case MilesPerHour:
+		return metersPerSecond * 2.236936
+	case Knots:
+		return metersPerSecond * 1.943844
+	case Beaufort:
+		// Empirical formula v = 0.836 * B^(3/2) m/s
+		return math.Min(12, math.Round(math.Pow(metersPerSecond/0.836, 2.0/3.0)))
+	}
+
+	return metersPerSecond
+}
+
+func ConvertPressure(hectopascal float64, unit Pressure) float64 {
+	switch unit {
+	case Pascal:
+		return hectopascal * 100
+	case InchesOfMercury:
+		return hectopascal * 0.02952998
+	case MillimetersOfMercury:
+		return hectopascal * 0.75006168
+	}
+
+	return hectopascal
+}
+
+func ConvertDistance(kilometers float64, unit Distance) float64 {
+	switch unit {
+	case Miles:
+		return kilometers * 0.6213712
+	case Meters:
+		return kilometers * 1000
+	}
+
+	return kilometers
+}
+
+func FormatTemperature(celsius float64, unit Temperature) string {
+	value := int(math.Round(ConvertTemperature(celsius, unit)))
+
+	if unit == Kelvin {
+		return fmt.Sprintf("%d K", value)
+	}
+
+	return fmt.Sprintf("%d%s", value, unit)
+}
+
+// Formats a temperature with a fixed number of decimal digits(e.g. the bounds of a confidence interval)
+func FormatTemperaturePrecise(celsius float64, unit Temperature, precision int) string {
+	value := fmt.Sprintf("%.*f", precision, ConvertTemperature(celsius, unit))
+
+	if unit == Kelvin {
+		return value + " K"
+	}
+
+	return value + string(unit)
+}
+
+func FormatTemperatureDelta(celsius float64, unit Temperature, precision int, signed bool) string {
+	format := "%.*f"
+	if signed {
+		format = "%+.*f"
+	}
+	value := fmt.Sprintf(format, precision, ConvertTemperatureDelta(celsius, unit))
+
+	if unit == Kelvin {
+		return value + " K"
+	}
+
+	return value + string(unit)
+}
+
+func FormatSpeed(metersPerSecond float64, unit Speed) string {
+	if unit == Beaufort {
+		return fmt.Sprintf("%d Bft", int(ConvertSpeed(metersPerSecond, unit)))
+	}
+
+	return fmt.Sprintf("%.1f %s", ConvertSpeed(metersPerSecond, unit), unit)
+}
+
+func FormatPressure(hectopascal float64, unit Pressure) string {
+	value := ConvertPressure(hectopascal, unit)
+
+	if unit == InchesOfMercury {
+		return fmt.Sprintf("%.2f %s", value, unit)
+	}
+
+	return fmt.Sprintf("%d %s", int(math.Round(value)), unit)
+}
+
+// Formats a pressure change over a three hours window
+func FormatPressureTendency(hectopascal float64, unit Pressure) string {
+	value := ConvertPressure(hectopascal, unit)
+
+	switch unit {
+	case InchesOfMercury:
+		return fmt.Sprintf("%.2f %s/3h", value, unit)
+	case Pascal:
+		return fmt.Sprintf("%d %s/3h", int(math.Round(value)), unit)
+	}
+
+	return fmt.Sprintf("%.1f %s/3h", value, unit)
+}
+
+func FormatDistance(kilometers float64, unit Distance) string {
+	value := ConvertDistance(kilometers, unit)
+
+	if unit == Meters {
+		return fmt.Sprintf("%d%s", int(math.Round(value)), unit)
+	}
+
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + string(unit)
+}
+
+func parseTemperature(unit string) (Temperature, error) {
+	switch strings.ToLower(strings.TrimPrefix(unit, "°")) {
+	case "c", "celsius":
+		return Celsius, nil
+	case "f", "fahrenheit":
+		return Fahrenheit, nil
+	case "k", "kelvin":
+		return Kelvin, nil
+	}
+
+	return "", fmt.Errorf("invalid temperature unit '%s'", unit)
+}
+
+func parseSpeed(unit string) (Speed, error) {
+	switch strings.ToLower(unit) {
+	case "kmh", "km/h", "kph":
+		return KilometersPerHour, nil
+	case "mph":
+		return MilesPerHour, nil
+	case "ms", "m/s", "mps":
+		return MetersPerSecond, nil
+	case "kn", "kt", "knots":
+		return Knots, nil
+	case "bft", "beaufort":
+		return Beaufort, nil
+	}
+
+	return "", fmt.Errorf("invalid speed unit '%s'", unit)
+}
+
+func parsePressure(unit string) (Pressure, error) {
+	switch strings.ToLower(unit) {
+	case "hpa", "mbar":
+		return Hectopascal, nil
+	case "pa":
+		return Pascal, nil
+	case "inhg":
+		return InchesOfMercury, nil
+	case "mmhg":
+		return MillimetersOfMercury, nil
+	}
+
+	return "", fmt.Errorf("invalid pressure unit '%s'", unit)
+}
+
+func parseDistance(unit string) (Distance, error) {
+	switch strings.ToLower(unit) {
+	case "km":
+		return Kilometers, nil
+	case "mi", "miles":
+		return Miles, nil
+	case "m":
+		return Meters, nil
+	}
+
+	return "", fmt.Errorf("invalid distance unit '%s'", unit)
+}
+
+// Parses a distance with an optional unit(e.g. '50km', '30mi' or '500m') and returns
+// it in kilometers. Distances without a unit are expressed in kilometers
+func ParseDistance(distance string) (float64, error) {
+	distance = strings.TrimSpace(distance)
+	number := strings.TrimRightFunc(distance, unicode.IsLetter)
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid distance '%s'", distance)
+	}
+
+	unit := Kilometers
+	if suffix := distance[len(number):]; suffix != "" {
+		if unit, err = parseDistance(suffix); err != nil {
+			return 0, err
+		}
+	}
+
+	return value / ConvertDistance(1, unit), nil
+}
+
+// Retrieves the unit system from the query parameters
+//
+// The base system is selected through the 'units' parameter(metric, imperial or si),
+// the legacy 'i' parameter is equivalent to 'units=imperial'. Each quantity
+// can then be overridden through the 'temp', 'wind', 'pressure' and 'distance' parameters.
+func FromQuery(query url.Values) (System, error) {
+	system := Metric
+	if query.Has("i") {
+		system = Imperial
+	}
+
+	if query.Has("units") {
+		switch strings.ToLower(query.Get("units")) {
+		case "metric":
+			system = Metric
+		case "imperial":
+			system = Imperial
+		case "si":
+			system = SI
+		default:
+			return System{}, errors.New("invalid unit system, use either 'metric', 'imperial' or 'si'")
+		}
+	}
+
+	var err error
+	if query.Has("temp") {
+		if system.Temperature, err = parseTemperature(query.Get("temp")); err != nil {
+			return System{}, err
+		}
+	}
+
+	if query.Has("wind") {
+		if system.Speed, err = parseSpeed(query.Get("wind")); err != nil {
+			return System{}, err
+		}
+	}
+
+	if query.Has("pressure") {
+		if system.Pressure, err = parsePressure(query.Get("pressure")); err != nil {
+			return System{}, err
+		}
+	}
+
+	if query.Has("distance") {
+		if system.Distance, err = parseDistance(query.Get("distance")); err != nil {
+			return System{}, err
+		}
+	}
+
+	return system, nil
+}
@@ -0,0 +1,100 @@
+package units
+
+import (
+	"math"
+	"net/url"
+	"testing"
+)
+
+type TestEntry struct {
+	Name     string
+	Got      string
+	Expected string
+}
+
+func TestFormat(t *testing.T) {
+	tests := []TestEntry{
+		{"Celsius", FormatTemperature(18.4, Celsius), "18°C"},
+		{"Fahrenheit", FormatTemperature(18, Fahrenheit), "64°F"},
+		{"Kelvin", FormatTemperature(18, Kelvin), "291 K"},
+		{"Precise Celsius", FormatTemperaturePrecise(14.237, Celsius, 2), "14.24°C"},
+		{"Precise Fahrenheit", FormatTemperaturePrecise(14.237, Fahrenheit, 2), "57.63°F"},
+		{"Precise Kelvin", FormatTemperaturePrecise(14.237, Kelvin, 1), "287.4 K"},
+		{"Fahrenheit delta", FormatTemperatureDelta(2, Fahrenheit, 4, false), "3.6000°F"},
+		{"Signed delta", FormatTemperatureDelta(-1.5, Celsius, 2, true), "-1.50°C"},
+		{"Kilometers per hour", FormatSpeed(3.61, KilometersPerHour), "13.0 km/h"},
+		{"Miles per hour", FormatSpeed(10, MilesPerHour), "22.4 mph"},
+		{"Knots", FormatSpeed(10, Knots), "19.4 kn"},
+		{"Beaufort", FormatSpeed(10, Beaufort), "5 Bft"},
+		{"Beaufort upper bound", FormatSpeed(80, Beaufort), "12 Bft"},
+		{"Hectopascal", FormatPressure(1015, Hectopascal), "1015 hPa"},
+		{"Inches of mercury", FormatPressure(1015, InchesOfMercury), "29.97 inHg"},
+		{"Millimeters of mercury", FormatPressure(1015, MillimetersOfMercury), "761 mmHg"},
+		{"Pressure tendency", FormatPressureTendency(-3.4, Hectopascal), "-3.4 hPa/3h"},
+		{"Kilometers", FormatDistance(10, Kilometers), "10km"},
+		{"Miles", FormatDistance(10, Miles), "6.2mi"},
+		{"Meters", FormatDistance(10, Meters), "10000m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Got != test.Expected {
+				t.Errorf("Got %s, wanted %s", test.Got, test.Expected)
+			}
+		})
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Query    string
+		Expected System
+		Valid    bool
+	}{
+		{"Default", "", Metric, true},
+		{"Legacy imperial flag", "i", Imperial, true},
+		{"SI system", "units=SI", SI, true},
+		{"Override", "units=imperial&temp=C&wind=bft", System{Celsius, Beaufort, InchesOfMercury, Miles}, true},
+		{"Escaped override", "wind=m%2Fs&pressure=mmHg&distance=mi", System{Celsius, MetersPerSecond, MillimetersOfMercury, Miles}, true},
+		{"Invalid system", "units=nautical", System{}, false},
+		{"Invalid override", "temp=R", System{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			query, _ := url.ParseQuery(test.Query)
+			got, err := FromQuery(query)
+
+			if (err == nil) != test.Valid || got != test.Expected {
+				t.Errorf("Got (%v, %v), wanted %v", got, err, test.Expected)
+			}
+		})
+	}
+}
+
+func TestParseDistance(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected float64
+		Valid    bool
+	}{
+		{"Kilometers", "50km", 50, true},
+		{"Miles", "10 mi", 16.0934, true},
+		{"Meters", "500m", 0.5, true},
+		{"No unit", "25", 25, true},
+		{"Invalid unit", "5ft", 0, false},
+		{"Negative", "-5km", 0, false},
+		{"Not a number", "far", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got, err := ParseDistance(test.Input)
+			if (err == nil) != test.Valid || math.Abs(got-test.Expected) > 1e-3 {
+				t.Errorf("Got (%v, %v), wanted %v", got, err, test.Expected)
+			}
+		})
+	}
+}
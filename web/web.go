@@ -0,0 +1,33 @@
+package web
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed templates static
+var assets embed.FS
+
+// Pages of the web interface, each one is rendered within the layout
+var pages = map[string]*template.Template{}
+
+func init() {
+	for _, page := range []string{"index", "weather", "metrics", "wind", "forecast", "moon", "stats", "error"} {
+		pages[page] = template.Must(template.ParseFS(assets, "templates/layout.html", "templates/"+page+".html"))
+	}
+}
+
+// Renders a page of the web interface
+func Render(w io.Writer, page string, data any) error {
+	return pages[page].ExecuteTemplate(w, "layout", data)
+}
+
+// Returns a handler serving the static files(i.e. the stylesheet)
+func Static() http.Handler {
+	static, _ := fs.Sub(assets, "static")
+
+	return http.FileServer(http.FS(static))
+}